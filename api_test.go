@@ -1,417 +1,2907 @@
-package main
-
-import (
-	"encoding/json"
-	"io"
-	"net/http"
-	"testing"
-
-	"github.com/gofiber/fiber/v2"
-	"inventory-project-testing/database"
-	"inventory-project-testing/models"
-	"inventory-project-testing/utils"
-	"github.com/steinfletcher/apitest"
-)
-
-
-// newApp returns application
-func newApp() *fiber.App {
-    // create a new application
-    var app *fiber.App = NewFiberApp()
-
-
-    // connect to the testing database
-    database.InitDatabase(utils.GetValue("DB_NAME"))
-
-    // return the application
-    return app
-}
-
-// getItem returns sample data for item entity
-func getItem() models.Item {
-    // connect to the test database
-    database.InitDatabase(utils.GetValue("DB_NAME"))
-
-
-    // seed the item data to the test database
-    // the sample data is stored in "item" variable
-    item, err := database.SeedItem()
-    if err != nil {
-        panic(err)
-    }
-
-
-    // return the sample data for item entity
-    return item
-}
-
-// cleanup performs clean up operation after the testing is completed
-func cleanup(res *http.Response, req *http.Request, apiTest *apitest.APITest) {
-    if http.StatusOK == res.StatusCode || http.StatusCreated == res.StatusCode {
-        database.CleanSeeders()
-    }
-}
-
-func TestSignup_Success(t *testing.T) {
-    // create a sample data for user
-    userData, err := utils.CreateFaker[models.User]()
-
-
-    if err != nil {
-        panic(err)
-    }
-
-
-    // create a sign up request
-    // the request values are filled
-    // with the value from the sample data
-    var userRequest *models.UserRequest = &models.UserRequest{
-        Email:    userData.Email,
-        Password: userData.Password,
-    }
-
-
-    // create a test
-    apitest.New().
-        // Debug().
-        // run the cleanup() function after the test is finished
-        Observe(cleanup).
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a POST request for sign up
-        Post("/api/v1/signup").
-        // set the request body
-        JSON(userRequest).
-        // expect the response status code is equals200
-        Expect(t).
-        Status(http.StatusOK).
-        End()
-}
-
-func TestSignup_ValidationFailed(t *testing.T) {
-    // create an empty request
-    var userRequest *models.UserRequest = &models.UserRequest{
-        Email:    "",
-        Password: "",
-    }
-
-
-    // create a test
-    apitest.New().
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a POST request for sign up
-        Post("/api/v1/signup").
-        // set the request body
-        JSON(userRequest).
-        // expect the response status code is equals 400
-        Expect(t).
-        Status(http.StatusBadRequest).
-        End()
-}
-
-func TestLogin_Success(t *testing.T) {
-    // connect to the test database
-    database.InitDatabase(utils.GetValue("DB_NAME"))
-
-
-    // seed the sample data for user entity
-    // after the data is seeded,
-    // the sample data is returned to the "user" variable
-    user, err := database.SeedUser()
-    if err != nil {
-        panic(err)
-    }
-
-
-    // create a request body for login
-    var userRequest *models.UserRequest = &models.UserRequest{
-        Email:    user.Email,
-        Password: user.Password,
-    }
-
-
-    // create a test
-    apitest.New().
-        // run the cleanup() function after the test is finished
-        Observe(cleanup).
-         // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a POST request for login
-        Post("/api/v1/login").
-        // set the request body
-        JSON(userRequest).
-        // expect the response status code is equals 200
-        Expect(t).
-        Status(http.StatusOK).
-        End()
-}
-
-func TestLogin_Failed(t *testing.T) {
-    // create a request body
-    var userRequest *models.UserRequest = &models.UserRequest{
-        Email:    "notfound@mail.com",
-        Password: "123123",
-    }
-
-
-    // create a test
-    apitest.New().
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a POST request for login
-        Post("/api/v1/login").
-        // set the request body
-        JSON(userRequest).
-        // expect the response status code is equals 500
-        Expect(t).
-        Status(http.StatusInternalServerError).
-        End()
-}
-
-// FiberToHandlerFunc returns handler function from the Fiber application
-func FiberToHandlerFunc(app *fiber.App) http.HandlerFunc {
-    // return the handler function
-    return func(w http.ResponseWriter, r *http.Request) {
-        // test the application with fiber
-        resp, err := app.Test(r)
-        if err != nil {
-            panic(err)
-        }
-
-
-        // copy the headers
-        for k, vv := range resp.Header {
-            for _, v := range vv {
-                w.Header().Add(k, v)
-            }
-        }
-        w.WriteHeader(resp.StatusCode)
-
-
-        if _, err := io.Copy(w, resp.Body); err != nil {
-            panic(err)
-        }
-    }
-}
-
-func TestGetItem_Success(t *testing.T) {
-    // get the sample data for item entity
-    var item models.Item = getItem()
-
-    // create a test
-    apitest.New().
-        // run the cleanup() function after the test is finished
-        Observe(cleanup).
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a GET request to get item data by ID
-        Get("/api/v1/items/" + item.ID).
-        // expect the response status code is equals 200
-        Expect(t).
-        Status(http.StatusOK).
-        End()
-}
-
-func TestGetItem_NotFound(t *testing.T) {
-    apitest.New().
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send GET request to get the item data by ID
-        Get("/api/v1/items/0").
-        // expect the response status code is equals 404
-        Expect(t).
-        Status(http.StatusNotFound).
-        End()
-}
-
-// getJWTToken returns bearer token with JWT
-func getJWTToken(t *testing.T) string {
-    // connect to the test database
-    database.InitDatabase(utils.GetValue("DB_NAME"))
-    // insert a sample data for user into the database
-    // the inserted sample data is returned into the "user variable"
-    user, err := database.SeedUser()
-    if err != nil {
-        panic(err)
-    }
-
-    // create a request for login
-    var userRequest *models.UserRequest = &models.UserRequest{
-        Email:    user.Email,
-        Password: user.Password,
-    }
-
-    // get the response from the login request
-    var resp *http.Response = apitest.New().
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        Post("/api/v1/login").
-        JSON(userRequest).
-        Expect(t).
-        Status(http.StatusOK).
-        End().Response
-
-    // create a variable called "response"
-    // to store the response body from the login request
-    var response *models.Response[string] = &models.Response[string]{}
-
-    // decode the response body into the "response" variable
-    json.NewDecoder(resp.Body).Decode(&response)
-
-    // get the JWT token
-    var token string = response.Data
-
-    // create a bearer token
-    var JWT_TOKEN = "Bearer " + token
-
-    // return the bearer token with JWT
-    return JWT_TOKEN
-}
-
-func TestCreateItem_Success(t *testing.T) {
-    // create a sample data for item
-    itemData, err := utils.CreateFaker[models.Item]()
-    if err != nil {
-        panic(err)
-    }
-
-    // create a request body to create a new item
-    // the request body is filled with the value
-    // from the sample data
-    var itemRequest *models.ItemRequest = &models.ItemRequest{
-        Name:     itemData.Name,
-        Price:    itemData.Price,
-        Quantity: itemData.Quantity,
-    }
-
-    // get the JWT token for authentication
-    var token string = getJWTToken(t)
-
-    // create a test
-    apitest.New().
-        // run the cleanup() function after the test is finished
-        Observe(cleanup).
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a POST request for creating a new item
-        Post("/api/v1/items").
-        // attach the JWT token into Authorization header
-        Header("Authorization", token).
-        // set the request body
-        JSON(itemRequest).
-        // expect the response status code is equals 201
-        Expect(t).
-        Status(http.StatusCreated).
-        End()
-}
-
-func TestCreateItem_ValidationFailed(t *testing.T) {
-    // create an empty request
-    var itemRequest *models.ItemRequest = &models.ItemRequest{
-        Name:     "",
-        Price:    0,
-        Quantity: 0,
-    }
-
-    // get the JWT token for authentication
-    var token string = getJWTToken(t)
-
-    // create a test
-    apitest.New().
-        // Debug().
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a POST request for creating a new item
-        Post("/api/v1/items").
-        // attach the JWT token into Authorization header
-        Header("Authorization", token).
-        // set the request body
-        JSON(itemRequest).
-        // expect the response status code is equals 400
-        Expect(t).
-        Status(http.StatusBadRequest).
-        End()
-}
-
-func TestUpdateItem_Success(t *testing.T) {
-    // get the sample data for item
-    var item models.Item = getItem()
-
-    // create a request body to update an item
-    var itemRequest *models.ItemRequest = &models.ItemRequest{
-        Name:     item.Name,
-        Price:    item.Price,
-        Quantity: item.Quantity,
-    }
-
-    // get the JWT token for authentication
-    var token string = getJWTToken(t)
-
-    // create a test
-    apitest.New().
-        // run the cleanup() function after the test is finished
-        Observe(cleanup).
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a PUT request for updating an item
-        Put("/api/v1/items/"+item.ID).
-        // attach the JWT token into Authorization header
-        Header("Authorization", token).
-        // set the request body
-        JSON(itemRequest).
-        // expect the response status code is equals 200
-        Expect(t).
-        Status(http.StatusOK).
-        End()
-}
-
-func TestUpdateItem_Failed(t *testing.T) {
-    // create a request
-    var itemRequest *models.ItemRequest = &models.ItemRequest{
-        Name:     "changed",
-        Price:    10,
-        Quantity: 10,
-    }
-
-    // get the JWT token for authentication
-    var token string = getJWTToken(t)
-
-    // create a test
-    apitest.New().
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a PUT request for updating an item
-        Put("/api/v1/items/0").
-        // attach the JWT token into Authorization header
-        Header("Authorization", token).
-        // set the request body
-        JSON(itemRequest).
-        // expect the response status code is equals 404
-        Expect(t).
-        Status(http.StatusNotFound).
-        End()
-}
-
-
-func TestDeleteItem_Success(t *testing.T) {
-    // create a sample data for item
-    // this sample data will be deleted
-    var item models.Item = getItem()
-
-    // get the JWT token for authentication
-    var token string = getJWTToken(t)
-
-    // create a test
-    apitest.New().
-        // add an application to be tested
-        HandlerFunc(FiberToHandlerFunc(newApp())).
-        // send a DELETE request for deleting an item
-        // the item ID is picked from the sample data
-        Delete("/api/v1/items/"+item.ID).
-        // attach the JWT token into Authorization header
-        Header("Authorization", token).
-        // expect the response status code is equals 200
-        Expect(t).
-        Status(http.StatusOK).
-        End()
-}
\ No newline at end of file
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+	"github.com/steinfletcher/apitest"
+)
+
+
+// newApp returns application
+func newApp() *fiber.App {
+    // create a new application
+    var app *fiber.App = NewFiberApp()
+
+
+    // connect to the testing database
+    database.InitDatabase(utils.GetValue("DB_NAME"))
+
+    // return the application
+    return app
+}
+
+// getItem returns sample data for item entity
+func getItem() models.Item {
+    // connect to the test database
+    database.InitDatabase(utils.GetValue("DB_NAME"))
+
+
+    // seed the item data to the test database
+    // the sample data is stored in "item" variable
+    item, err := database.SeedItem()
+    if err != nil {
+        panic(err)
+    }
+
+
+    // return the sample data for item entity
+    return item
+}
+
+// cleanup performs clean up operation after the testing is completed
+func cleanup(res *http.Response, req *http.Request, apiTest *apitest.APITest) {
+    if http.StatusOK == res.StatusCode || http.StatusCreated == res.StatusCode {
+        database.CleanSeeders()
+    }
+}
+
+func TestSignup_Success(t *testing.T) {
+    // create a sample data for user
+    userData, err := utils.CreateFaker[models.User]()
+
+
+    if err != nil {
+        panic(err)
+    }
+
+
+    // create a sign up request
+    // the request values are filled
+    // with the value from the sample data
+    var userRequest *models.UserRequest = &models.UserRequest{
+        Email:    userData.Email,
+        Password: userData.Password,
+    }
+
+
+    // create a test
+    apitest.New().
+        // Debug().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for sign up
+        Post("/api/v1/signup").
+        // set the request body
+        JSON(userRequest).
+        // expect the response status code is equals200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestSignup_ValidationFailed(t *testing.T) {
+    // create an empty request
+    var userRequest *models.UserRequest = &models.UserRequest{
+        Email:    "",
+        Password: "",
+    }
+
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for sign up
+        Post("/api/v1/signup").
+        // set the request body
+        JSON(userRequest).
+        // expect the response status code is equals 400
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestLogin_Success(t *testing.T) {
+    // connect to the test database
+    database.InitDatabase(utils.GetValue("DB_NAME"))
+
+
+    // seed the sample data for user entity
+    // after the data is seeded,
+    // the sample data is returned to the "user" variable
+    user, err := database.SeedUser()
+    if err != nil {
+        panic(err)
+    }
+
+
+    // create a request body for login
+    var userRequest *models.UserRequest = &models.UserRequest{
+        Email:    user.Email,
+        Password: user.Password,
+    }
+
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+         // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for login
+        Post("/api/v1/login").
+        // set the request body
+        JSON(userRequest).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestLogin_Failed(t *testing.T) {
+    // create a request body
+    var userRequest *models.UserRequest = &models.UserRequest{
+        Email:    "notfound@mail.com",
+        Password: "123123",
+    }
+
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for login
+        Post("/api/v1/login").
+        // set the request body
+        JSON(userRequest).
+        // expect the response status code is equals 401
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+}
+
+// FiberToHandlerFunc returns handler function from the Fiber application
+func FiberToHandlerFunc(app *fiber.App) http.HandlerFunc {
+    // return the handler function
+    return func(w http.ResponseWriter, r *http.Request) {
+        // test the application with fiber
+        resp, err := app.Test(r)
+        if err != nil {
+            panic(err)
+        }
+
+
+        // copy the headers
+        for k, vv := range resp.Header {
+            for _, v := range vv {
+                w.Header().Add(k, v)
+            }
+        }
+        w.WriteHeader(resp.StatusCode)
+
+
+        if _, err := io.Copy(w, resp.Body); err != nil {
+            panic(err)
+        }
+    }
+}
+
+func TestGetItem_Success(t *testing.T) {
+    // get the sample data for item entity
+    var item models.Item = getItem()
+
+    // get the JWT token for a member of the organization of the item
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request to get item data by ID
+        Get("/api/v1/items/" + item.ID).
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestGetItem_NotFound(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send GET request to get the item data by ID
+        Get("/api/v1/items/0").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 404
+        Expect(t).
+        Status(http.StatusNotFound).
+        End()
+}
+
+// getTokenPair returns the token pair from the login request
+func getTokenPair(t *testing.T) models.TokenPair {
+    return getTokenPairWithRole(t, models.RoleManager)
+}
+
+// getTokenPairWithRole returns the token pair for a user with the given role
+func getTokenPairWithRole(t *testing.T, role string) models.TokenPair {
+    // connect to the test database
+    database.InitDatabase(utils.GetValue("DB_NAME"))
+    // insert a sample data for user into the database
+    // the inserted sample data is returned into the "user variable"
+    user, err := database.SeedUserWithRole(role)
+    if err != nil {
+        panic(err)
+    }
+
+    // create a request for login
+    var userRequest *models.UserRequest = &models.UserRequest{
+        Email:    user.Email,
+        Password: user.Password,
+    }
+
+    // get the response from the login request
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/login").
+        JSON(userRequest).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    // create a variable called "response"
+    // to store the response body from the login request
+    var response *models.Response[models.TokenPair] = &models.Response[models.TokenPair]{}
+
+    // decode the response body into the "response" variable
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // return the token pair
+    return response.Data
+}
+
+// getJWTToken returns bearer token with JWT
+func getJWTToken(t *testing.T) string {
+    // get the JWT token
+    var token string = getTokenPair(t).AccessToken
+
+    // create a bearer token
+    var JWT_TOKEN = "Bearer " + token
+
+    // return the bearer token with JWT
+    return JWT_TOKEN
+}
+
+func TestCreateItem_Success(t *testing.T) {
+    // create a sample data for item
+    itemData, err := utils.CreateFaker[models.Item]()
+    if err != nil {
+        panic(err)
+    }
+
+    // create a request body to create a new item
+    // the request body is filled with the value
+    // from the sample data
+    var itemRequest *models.ItemRequest = &models.ItemRequest{
+        Name:     itemData.Name,
+        Price:    itemData.Price,
+        Quantity: itemData.Quantity,
+    }
+
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for creating a new item
+        Post("/api/v1/items").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(itemRequest).
+        // expect the response status code is equals 201
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+}
+
+func TestCreateItem_ValidationFailed(t *testing.T) {
+    // create an empty request
+    var itemRequest *models.ItemRequest = &models.ItemRequest{
+        Name:     "",
+        Price:    0,
+        Quantity: 0,
+    }
+
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // Debug().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for creating a new item
+        Post("/api/v1/items").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(itemRequest).
+        // expect the response status code is equals 400
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestUpdateItem_Success(t *testing.T) {
+    // get the sample data for item
+    var item models.Item = getItem()
+
+    // create a request body to update an item
+    var itemRequest *models.ItemRequest = &models.ItemRequest{
+        Name:     item.Name,
+        Price:    item.Price,
+        Quantity: item.Quantity,
+    }
+
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a PUT request for updating an item
+        Put("/api/v1/items/"+item.ID).
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(itemRequest).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestUpdateItem_Failed(t *testing.T) {
+    // create a request
+    var itemRequest *models.ItemRequest = &models.ItemRequest{
+        Name:     "changed",
+        Price:    10,
+        Quantity: 10,
+    }
+
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a PUT request for updating an item
+        Put("/api/v1/items/0").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(itemRequest).
+        // expect the response status code is equals 404
+        Expect(t).
+        Status(http.StatusNotFound).
+        End()
+}
+
+
+func TestDeleteItem_Success(t *testing.T) {
+    // create a sample data for item
+    // this sample data will be deleted
+    var item models.Item = getItem()
+
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a DELETE request for deleting an item
+        // the item ID is picked from the sample data
+        Delete("/api/v1/items/"+item.ID).
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestRefreshToken_Success(t *testing.T) {
+    // get the token pair from the login request
+    var tokenPair models.TokenPair = getTokenPair(t)
+
+    // create a request body to refresh the token
+    var refreshRequest *models.RefreshRequest = &models.RefreshRequest{
+        RefreshToken: tokenPair.RefreshToken,
+    }
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for refreshing the token
+        Post("/api/v1/token/refresh").
+        // set the request body
+        JSON(refreshRequest).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestRefreshToken_Reused(t *testing.T) {
+    // get the token pair from the login request
+    var tokenPair models.TokenPair = getTokenPair(t)
+
+    // create a request body to refresh the token
+    var refreshRequest *models.RefreshRequest = &models.RefreshRequest{
+        RefreshToken: tokenPair.RefreshToken,
+    }
+
+    // use the refresh token for the first time
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/token/refresh").
+        JSON(refreshRequest).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request with the same refresh token
+        Post("/api/v1/token/refresh").
+        // set the request body
+        JSON(refreshRequest).
+        // expect the response status code is equals 401
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+}
+func TestCreateItem_UserNotFound(t *testing.T) {
+    // create a request body to create a new item
+    var itemRequest *models.ItemRequest = &models.ItemRequest{
+        Name:     "coffee",
+        Price:    10,
+        Quantity: 10,
+    }
+
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // remove the user who owns the JWT token
+    database.CleanSeeders()
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for creating a new item
+        Post("/api/v1/items").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(itemRequest).
+        // expect the response status code is equals 401
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+}
+
+func TestCreateItem_Forbidden(t *testing.T) {
+    // create a request body to create a new item
+    var itemRequest *models.ItemRequest = &models.ItemRequest{
+        Name:     "coffee",
+        Price:    10,
+        Quantity: 10,
+    }
+
+    // get the JWT token for a user with the viewer role
+    var token string = "Bearer " + getTokenPairWithRole(t, models.RoleViewer).AccessToken
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for creating a new item
+        Post("/api/v1/items").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(itemRequest).
+        // expect the response status code is equals 403
+        Expect(t).
+        Status(http.StatusForbidden).
+        End()
+}
+
+func TestLogout_Success(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // log out with the JWT token
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/logout").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request with the revoked JWT token
+        Post("/api/v1/items").
+        // attach the revoked JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(&models.ItemRequest{Name: "coffee", Price: 10, Quantity: 10}).
+        // expect the response status code is equals 401
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+}
+
+func TestRevokeAllSessions_Success(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // get the ID of the user from the JWT token
+    var userID string = getUserID(t, token)
+
+    // revoke every session of the user
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/users/" + userID + "/sessions/revoke-all").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for logging out with the revoked JWT token
+        Post("/api/v1/logout").
+        // attach the revoked JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 401
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+}
+
+// getUserID returns the user ID from the bearer token
+func getUserID(t *testing.T, bearerToken string) string {
+    // parse the JWT token without verification
+    token, _, err := new(jwt.Parser).ParseUnverified(strings.TrimPrefix(bearerToken, "Bearer "), jwt.MapClaims{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // return the subject of the JWT token
+    return token.Claims.(jwt.MapClaims)["sub"].(string)
+}
+
+func TestGetJWKS_Success(t *testing.T) {
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request to get the public keys
+        Get("/.well-known/jwks.json").
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+// getAPIKey returns a new API key with the given scopes
+func getAPIKey(t *testing.T, scopes []string) string {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a request body to create a new API key
+    var apiKeyRequest *models.APIKeyRequest = &models.APIKeyRequest{
+        Name:   "scanner",
+        Scopes: scopes,
+    }
+
+    // get the response from the API key creation request
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/api-keys").
+        Header("Authorization", token).
+        JSON(apiKeyRequest).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    // decode the response body into the "response" variable
+    var response *models.Response[models.CreatedAPIKey] = &models.Response[models.CreatedAPIKey]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // return the plain API key
+    return response.Data.Key
+}
+
+func TestCreateItem_WithAPIKey(t *testing.T) {
+    // get the API key without scopes
+    var apiKey string = getAPIKey(t, nil)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for creating a new item
+        Post("/api/v1/items").
+        // attach the API key into X-API-Key header
+        Header("X-API-Key", apiKey).
+        // set the request body
+        JSON(&models.ItemRequest{Name: "coffee", Price: 10, Quantity: 10}).
+        // expect the response status code is equals 201
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+}
+
+func TestCreateItem_APIKeyScopeDenied(t *testing.T) {
+    // get the API key that can only read items
+    var apiKey string = getAPIKey(t, []string{models.PermissionItemsRead})
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for creating a new item
+        Post("/api/v1/items").
+        // attach the API key into X-API-Key header
+        Header("X-API-Key", apiKey).
+        // set the request body
+        JSON(&models.ItemRequest{Name: "coffee", Price: 10, Quantity: 10}).
+        // expect the response status code is equals 403
+        Expect(t).
+        Status(http.StatusForbidden).
+        End()
+}
+
+func TestEnrollTOTP_APIKeyForbidden(t *testing.T) {
+    // get the API key without scopes
+    var apiKey string = getAPIKey(t, nil)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request to enroll a new TOTP secret
+        Post("/api/v1/me/mfa/totp").
+        // attach the API key into X-API-Key header
+        Header("X-API-Key", apiKey).
+        // expect the response status code is equals 403
+        Expect(t).
+        Status(http.StatusForbidden).
+        End()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+}
+
+// mailRecorder keeps the emails sent by the application
+type mailRecorder struct {
+    mails []utils.Mail
+}
+
+// Send stores the email in the recorder
+func (recorder *mailRecorder) Send(mail utils.Mail) error {
+    recorder.mails = append(recorder.mails, mail)
+    return nil
+}
+
+// lastToken returns the token from the link in the last email
+func (recorder *mailRecorder) lastToken(t *testing.T) string {
+    if len(recorder.mails) == 0 {
+        t.Fatal("no email has been sent")
+    }
+
+    var body string = recorder.mails[len(recorder.mails)-1].Body
+    return body[strings.LastIndex(body, "token=")+len("token="):]
+}
+
+func TestForgotPassword_UnknownEmail(t *testing.T) {
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for an email that is not registered
+        Post("/api/v1/password/forgot").
+        // set the request body
+        JSON(&models.ForgotPasswordRequest{Email: "notfound@mail.com"}).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestResetPassword_Success(t *testing.T) {
+    // record the emails sent by the application
+    var recorder *mailRecorder = &mailRecorder{}
+    services.SetMailer(recorder)
+
+    // connect to the test database
+    database.InitDatabase(utils.GetValue("DB_NAME"))
+
+    // seed the sample data for user entity
+    user, err := database.SeedUser()
+    if err != nil {
+        panic(err)
+    }
+
+    // request the password reset email
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/password/forgot").
+        JSON(&models.ForgotPasswordRequest{Email: user.Email}).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a request body to reset the password
+    var resetRequest *models.ResetPasswordRequest = &models.ResetPasswordRequest{
+        Token:    recorder.lastToken(t),
+        Password: "new-password",
+    }
+
+    // reset the password with the token from the email
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/password/reset").
+        JSON(resetRequest).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request with the same reset token
+        Post("/api/v1/password/reset").
+        // set the request body
+        JSON(resetRequest).
+        // expect the response status code is equals 400
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestVerifyEmail_Success(t *testing.T) {
+    // record the emails sent by the application
+    var recorder *mailRecorder = &mailRecorder{}
+    services.SetMailer(recorder)
+
+    // create a sample data for user
+    userData, err := utils.CreateFaker[models.User]()
+    if err != nil {
+        panic(err)
+    }
+
+    // sign up, the verification email is sent
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/signup").
+        JSON(&models.UserRequest{Email: userData.Email, Password: userData.Password}).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request with the token from the email
+        Get("/api/v1/verify-email").
+        Query("token", recorder.lastToken(t)).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request with an unknown token
+        Get("/api/v1/verify-email").
+        Query("token", "invalid").
+        // expect the response status code is equals 400
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestLogin_Backoff(t *testing.T) {
+    // connect to the test database
+    database.InitDatabase(utils.GetValue("DB_NAME"))
+
+    // seed the sample data for user entity
+    user, err := database.SeedUser()
+    if err != nil {
+        panic(err)
+    }
+
+    // send a login request with a wrong password
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/login").
+        JSON(&models.UserRequest{Email: user.Email, Password: "wrong-password"}).
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a login request right after the failed attempt
+        Post("/api/v1/login").
+        // set the request body with the correct password
+        JSON(&models.UserRequest{Email: user.Email, Password: user.Password}).
+        // expect the response status code is equals 429
+        Expect(t).
+        Status(http.StatusTooManyRequests).
+        End()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+}
+
+func TestLoginWithMFA_Success(t *testing.T) {
+    // connect to the test database
+    database.InitDatabase(utils.GetValue("DB_NAME"))
+
+    // seed the sample data for user entity
+    user, err := database.SeedUser()
+    if err != nil {
+        panic(err)
+    }
+
+    // log in with the password
+    var loginResponse *models.Response[models.LoginResult] = &models.Response[models.LoginResult]{}
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/login").
+        JSON(&models.UserRequest{Email: user.Email, Password: user.Password}).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+    json.NewDecoder(resp.Body).Decode(&loginResponse)
+    var token string = "Bearer " + loginResponse.Data.AccessToken
+
+    // start the TOTP enrollment
+    var enrollResponse *models.Response[models.TOTPEnrollment] = &models.Response[models.TOTPEnrollment]{}
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/me/mfa/totp").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+    json.NewDecoder(resp.Body).Decode(&enrollResponse)
+
+    // confirm the TOTP enrollment with the current code
+    code, err := utils.GenerateTOTPCode(enrollResponse.Data.Secret, time.Now().Unix()/30)
+    if err != nil {
+        panic(err)
+    }
+
+    var confirmResponse *models.Response[models.RecoveryCodes] = &models.Response[models.RecoveryCodes]{}
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/me/mfa/totp/confirm").
+        Header("Authorization", token).
+        JSON(&models.TOTPCodeRequest{Code: code}).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+    json.NewDecoder(resp.Body).Decode(&confirmResponse)
+
+    // log in again, the MFA token is returned
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/login").
+        JSON(&models.UserRequest{Email: user.Email, Password: user.Password}).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+    json.NewDecoder(resp.Body).Decode(&loginResponse)
+
+    if !loginResponse.Data.MFARequired {
+        t.Fatal("mfa is not required")
+    }
+
+    // create a request body with a recovery code
+    var mfaRequest *models.MFALoginRequest = &models.MFALoginRequest{
+        MFAToken: loginResponse.Data.MFAToken,
+        Code:     confirmResponse.Data.RecoveryCodes[0],
+    }
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request to finish the login
+        Post("/api/v1/login/mfa").
+        // set the request body
+        JSON(mfaRequest).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestGetUsers_Success(t *testing.T) {
+    // get the JWT token for a user with the admin role
+    var token string = "Bearer " + getTokenPairWithRole(t, models.RoleAdmin).AccessToken
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request for listing the users
+        Get("/api/v1/users").
+        Query("per_page", "10").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 200
+        // the password hash is not included in the response
+        Expect(t).
+        Status(http.StatusOK).
+        Assert(assertNoPassword).
+        End()
+}
+
+// assertNoPassword makes sure the password is not included in the response body
+func assertNoPassword(res *http.Response, req *http.Request) error {
+    body, err := io.ReadAll(res.Body)
+    if err != nil {
+        return err
+    }
+
+    if strings.Contains(string(body), `"password"`) {
+        return errors.New("password is included in the response")
+    }
+
+    return nil
+}
+
+func TestGetUsers_Forbidden(t *testing.T) {
+    // get the JWT token for a user with the manager role
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request for listing the users
+        Get("/api/v1/users").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 403
+        Expect(t).
+        Status(http.StatusForbidden).
+        End()
+}
+
+func TestGetUsers_SearchWildcard(t *testing.T) {
+    // get the JWT token for a user with the admin role
+    var token string = "Bearer " + getTokenPairWithRole(t, models.RoleAdmin).AccessToken
+
+    // seed a user whose email matches the search only if the underscore is a wildcard
+    user, err := database.SeedUser()
+    if err != nil {
+        panic(err)
+    }
+
+    database.DB.Model(&models.User{}).Where("id = ?", user.ID).Update("email", "axb@example.com")
+
+    // create a test
+    var resp *http.Response = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request to search the users with an underscore
+        Get("/api/v1/users").
+        Query("q", "a_b").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.PaginatedResponse[[]models.User] = &models.PaginatedResponse[[]models.User]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the underscore is matched as a normal character
+    if len(response.Data) != 0 {
+        t.Fatalf("unexpected users: %+v", response.Data)
+    }
+}
+
+func TestDeleteUser_RevokesAPIKeys(t *testing.T) {
+    // get the JWT tokens for the admin and the user to be deleted
+    var adminToken string = "Bearer " + getTokenPairWithRole(t, models.RoleAdmin).AccessToken
+    var token string = getJWTToken(t)
+
+    // create an API key for the user
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/api-keys").
+        Header("Authorization", token).
+        JSON(&models.APIKeyRequest{Name: "scanner"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var apiKey *models.Response[models.CreatedAPIKey] = &models.Response[models.CreatedAPIKey]{}
+    json.NewDecoder(resp.Body).Decode(&apiKey)
+
+    // delete the user
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Delete("/api/v1/users/" + getUserID(t, token)).
+        Header("Authorization", adminToken).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request with the API key of the deleted user
+        Get("/api/v1/items").
+        // attach the API key into X-API-Key header
+        Header("X-API-Key", apiKey.Data.Key).
+        // expect the response status code is equals 401
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+}
+
+func TestUpdateUser_Disable(t *testing.T) {
+    // get the JWT tokens for the admin and the user to be disabled
+    var adminToken string = "Bearer " + getTokenPairWithRole(t, models.RoleAdmin).AccessToken
+    var token string = getJWTToken(t)
+
+    // get the ID of the user from the JWT token
+    var userID string = getUserID(t, token)
+
+    // disable the user
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Patch("/api/v1/users/" + userID).
+        Header("Authorization", adminToken).
+        JSON(`{"disabled": true}`).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request for logging out with the token of the disabled user
+        Post("/api/v1/logout").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 401
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+}
+
+func TestGetProfile_Success(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request for getting the profile
+        Get("/api/v1/me").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 200
+        // the password hash is not included in the response
+        Expect(t).
+        Status(http.StatusOK).
+        Assert(assertNoPassword).
+        End()
+}
+
+func TestUpdateProfile_ValidationFailed(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a PATCH request with an invalid timezone
+        Patch("/api/v1/me").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        JSON(`{"display_name": "John", "timezone": "Mars/Olympus"}`).
+        // expect the response status code is equals 400
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestChangePassword_Success(t *testing.T) {
+    // connect to the test database
+    database.InitDatabase(utils.GetValue("DB_NAME"))
+    // insert a sample data for user into the database
+    user, err := database.SeedUser()
+    if err != nil {
+        panic(err)
+    }
+
+    // log in with the current password
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/login").
+        JSON(models.UserRequest{Email: user.Email, Password: user.Password}).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.Response[models.TokenPair] = &models.Response[models.TokenPair]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    var token string = "Bearer " + response.Data.AccessToken
+
+    // change the password
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/me/password").
+        Header("Authorization", token).
+        JSON(models.ChangePasswordRequest{CurrentPassword: user.Password, NewPassword: "new-password"}).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request with the token issued before the password change
+        Get("/api/v1/me").
+        // attach the revoked JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 401
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+}
+
+func TestChangePassword_InvalidCurrentPassword(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request with a wrong current password
+        Post("/api/v1/me/password").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        JSON(models.ChangePasswordRequest{CurrentPassword: "wrong-password", NewPassword: "new-password"}).
+        // expect the response status code is equals 400
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestGetItem_OtherOrganization(t *testing.T) {
+    // get the sample data for item entity
+    var item models.Item = getItem()
+
+    // move the item into another organization
+    database.DB.Model(&item).Update("organization_id", "other-organization")
+
+    // get the JWT token for a member of the seeded organization
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request to get the item of the other organization
+        Get("/api/v1/items/" + item.ID).
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 404
+        Expect(t).
+        Status(http.StatusNotFound).
+        End()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+}
+
+func TestSwitchOrganization_NotMember(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request to switch into an unknown organization
+        Post("/api/v1/organizations/other-organization/switch").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 403
+        Expect(t).
+        Status(http.StatusForbidden).
+        End()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+}
+
+func TestCreateOrganization_VerifiedUser(t *testing.T) {
+    // get the JWT token for a verified user with the viewer role
+    var token string = "Bearer " + getTokenPairWithRole(t, models.RoleViewer).AccessToken
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request to create a new organization
+        Post("/api/v1/organizations").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(&models.OrganizationRequest{Name: "Corner Shop", Slug: "cornershop"}).
+        // expect the response status code is equals 201
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+}
+
+func TestAcceptInvitation_Success(t *testing.T) {
+    // record the emails sent by the application
+    var recorder *mailRecorder = &mailRecorder{}
+    services.SetMailer(recorder)
+
+    // get the JWT token for a user with the admin role
+    var token string = "Bearer " + getTokenPairWithRole(t, models.RoleAdmin).AccessToken
+
+    // invite a new email into the organization
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/invitations").
+        Header("Authorization", token).
+        JSON(&models.InvitationRequest{Email: "clerk@example.com", Role: models.RoleClerk}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // create a request body to accept the invitation
+    var acceptRequest *models.AcceptInvitationRequest = &models.AcceptInvitationRequest{
+        Token:    recorder.lastToken(t),
+        Password: "clerk-password",
+    }
+
+    // accept the invitation with the token from the email
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/invitations/accept").
+        JSON(acceptRequest).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request with the same invitation token
+        Post("/api/v1/invitations/accept").
+        // set the request body
+        JSON(acceptRequest).
+        // expect the response status code is equals 400
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestAcceptInvitation_ExistingUserWithMFA(t *testing.T) {
+    // record the emails sent by the application
+    var recorder *mailRecorder = &mailRecorder{}
+    services.SetMailer(recorder)
+
+    // get the JWT token for a user with the admin role
+    var token string = "Bearer " + getTokenPairWithRole(t, models.RoleAdmin).AccessToken
+
+    // seed an existing user with TOTP that is not a member of the organization
+    user, err := database.SeedUser()
+    if err != nil {
+        panic(err)
+    }
+
+    database.DB.Where("user_id = ?", user.ID).Delete(&models.Membership{})
+    database.DB.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]any{"totp_secret": "JBSWY3DPEHPK3PXP", "totp_enabled_at": time.Now()})
+
+    // invite the existing user into the organization
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/invitations").
+        Header("Authorization", token).
+        JSON(&models.InvitationRequest{Email: user.Email, Role: models.RoleClerk}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request to accept the invitation without the password
+        Post("/api/v1/invitations/accept").
+        // set the request body
+        JSON(&models.AcceptInvitationRequest{Token: recorder.lastToken(t)}).
+        // expect the response status code is equals 200
+        // the token pair is not returned for the existing user
+        Expect(t).
+        Status(http.StatusOK).
+        Assert(assertNoTokens).
+        End()
+}
+
+// assertNoTokens makes sure the tokens are not included in the response body
+func assertNoTokens(res *http.Response, req *http.Request) error {
+    body, err := io.ReadAll(res.Body)
+    if err != nil {
+        return err
+    }
+
+    if strings.Contains(string(body), `"access_token"`) || strings.Contains(string(body), `"refresh_token"`) {
+        return errors.New("tokens are included in the response")
+    }
+
+    return nil
+}
+
+func TestCreateInvitation_Forbidden(t *testing.T) {
+    // get the JWT token for a user with the manager role
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request to invite an email
+        Post("/api/v1/invitations").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(&models.InvitationRequest{Email: "clerk@example.com", Role: models.RoleClerk}).
+        // expect the response status code is equals 403
+        Expect(t).
+        Status(http.StatusForbidden).
+        End()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+}
+
+// stubOIDCProvider is an in-process OpenID Connect provider for testing
+type stubOIDCProvider struct {
+    server *httptest.Server
+    key    *rsa.PrivateKey
+    email  string
+    codes  map[string]url.Values
+}
+
+// newStubOIDCProvider starts the provider that logs in the given email
+func newStubOIDCProvider(t *testing.T, email string) *stubOIDCProvider {
+    key, err := rsa.GenerateKey(rand.Reader, 2048)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var provider *stubOIDCProvider = &stubOIDCProvider{key: key, email: email, codes: map[string]url.Values{}}
+
+    var mux *http.ServeMux = http.NewServeMux()
+
+    // serve the public key of the provider
+    mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+        json.NewEncoder(w).Encode(models.JSONWebKeySet{Keys: []models.JSONWebKey{{
+            KeyType:   "RSA",
+            KeyID:     "stub",
+            Use:       "sig",
+            Algorithm: "RS256",
+            Modulus:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+            Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+        }}})
+    })
+
+    // exchange the authorization code for the ID token
+    mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+        r.ParseForm()
+
+        // the code can only be used once with the matching code verifier
+        request, ok := provider.codes[r.Form.Get("code")]
+        delete(provider.codes, r.Form.Get("code"))
+
+        if !ok || utils.GetPKCEChallenge(r.Form.Get("code_verifier")) != request.Get("code_challenge") {
+            w.WriteHeader(http.StatusBadRequest)
+            return
+        }
+
+        token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+            "iss":            provider.server.URL,
+            "aud":            request.Get("client_id"),
+            "sub":            "stub-subject",
+            "email":          provider.email,
+            "email_verified": true,
+            "nonce":          request.Get("nonce"),
+            "iat":            time.Now().Unix(),
+            "exp":            time.Now().Add(time.Minute).Unix(),
+        })
+        token.Header["kid"] = "stub"
+
+        idToken, _ := token.SignedString(key)
+        json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+    })
+
+    provider.server = httptest.NewServer(mux)
+    t.Cleanup(provider.server.Close)
+
+    // use the stub provider in the services
+    services.SetOIDCProvider(&utils.OIDCProvider{
+        Issuer:                provider.server.URL,
+        ClientID:              "inventory",
+        RedirectURL:           "http://localhost/api/v1/oidc/callback",
+        Scopes:                []string{"openid", "email"},
+        AuthorizationEndpoint: provider.server.URL + "/authorize",
+        TokenEndpoint:         provider.server.URL + "/token",
+        JWKSURI:               provider.server.URL + "/jwks",
+        Client:                provider.server.Client(),
+    })
+    t.Cleanup(func() { services.SetOIDCProvider(nil) })
+
+    return provider
+}
+
+// authorize logs in at the provider and returns the authorization code
+func (provider *stubOIDCProvider) authorize(t *testing.T, authURL string) string {
+    parsedURL, err := url.Parse(authURL)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var code string = uuid.New().String()
+    provider.codes[code] = parsedURL.Query()
+    return code
+}
+
+func TestOIDCLogin_Success(t *testing.T) {
+    // connect to the test database
+    database.InitDatabase(utils.GetValue("DB_NAME"))
+
+    // seed the sample data for user entity
+    user, err := database.SeedUser()
+    if err != nil {
+        panic(err)
+    }
+
+    // start the stub provider for the seeded user
+    var provider *stubOIDCProvider = newStubOIDCProvider(t, user.Email)
+
+    // start the login, the application redirects to the provider
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/oidc/login").
+        Expect(t).
+        Status(http.StatusFound).
+        End().Response
+
+    var authURL string = resp.Header.Get("Location")
+    parsedURL, _ := url.Parse(authURL)
+
+    // get the authorization code from the provider
+    var code string = provider.authorize(t, authURL)
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request to complete the login
+        Get("/api/v1/oidc/callback").
+        Query("state", parsedURL.Query().Get("state")).
+        Query("code", code).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+}
+
+func TestOIDCLogin_InvalidState(t *testing.T) {
+    // start the stub provider
+    newStubOIDCProvider(t, "unknown@example.com")
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request with an unknown state
+        Get("/api/v1/oidc/callback").
+        Query("state", "unknown-state").
+        Query("code", "unknown-code").
+        // expect the response status code is equals 401
+        Expect(t).
+        Status(http.StatusUnauthorized).
+        End()
+}
+
+func TestGetAllItems_Pagination(t *testing.T) {
+    // seed three items into the organization
+    for i := 0; i < 3; i++ {
+        getItem()
+    }
+
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // get the first page of the items
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items").
+        Query("limit", "2").
+        Query("sort", "-price,name").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.PaginatedResponse[[]models.Item] = &models.PaginatedResponse[[]models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    if len(response.Data) != 2 || response.Pagination.Total != 3 || response.Pagination.NextCursor == "" {
+        t.Fatalf("unexpected first page: %+v", response.Pagination)
+    }
+
+    // get the second page with the cursor
+    resp = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items").
+        Query("limit", "2").
+        Query("sort", "-price,name").
+        Query("cursor", response.Pagination.NextCursor).
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    response = &models.PaginatedResponse[[]models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the last item is returned without a next cursor
+    if len(response.Data) != 1 || response.Pagination.NextCursor != "" {
+        t.Fatalf("unexpected second page: %+v", response.Pagination)
+    }
+}
+
+func TestGetAllItems_NameWildcard(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item whose name matches the filter only if the percent sign is a wildcard
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "100 Cotton Shirt", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // create a test
+    var resp *http.Response = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request to filter the items with a percent sign
+        Get("/api/v1/items").
+        Query("name", "100% Cotton").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.PaginatedResponse[[]models.Item] = &models.PaginatedResponse[[]models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the percent sign is matched as a normal character
+    if len(response.Data) != 0 {
+        t.Fatalf("unexpected items: %+v", response.Data)
+    }
+}
+
+func TestGetAllItems_InvalidSort(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request with an unknown sort field
+        Get("/api/v1/items").
+        Query("sort", "password").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 400
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+}
+
+func TestSearchItems_Typo(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a new item that is added into the search index
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Arabica Coffee Beans", Description: "medium roast", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // search the item with a misspelled name
+    var resp *http.Response = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/search").
+        Query("q", "cofee").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.Response[[]models.ItemSearchResult] = &models.Response[[]models.ItemSearchResult]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the item is found and the matched word is highlighted
+    if len(response.Data) != 1 || response.Data[0].Highlights["name"] != "Arabica <mark>Coffee</mark> Beans" {
+        t.Fatalf("unexpected search results: %+v", response.Data)
+    }
+}
+
+func TestSearchItems_HighlightEscaped(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a new item with the HTML characters in the name
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Salt & Pepper <script>", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // search the item with a word of the name
+    var resp *http.Response = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/search").
+        Query("q", "pepper").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.Response[[]models.ItemSearchResult] = &models.Response[[]models.ItemSearchResult]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the name is escaped and only the matched word is wrapped with the <mark> tag
+    if len(response.Data) != 1 || response.Data[0].Highlights["name"] != "Salt &amp; <mark>Pepper</mark> &lt;script&gt;" {
+        t.Fatalf("unexpected search results: %+v", response.Data)
+    }
+}
+
+func TestSuggestItems_Success(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a new item that is added into the search index
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Coffee Mug", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // get the suggestions for the prefix of the item name
+    var resp *http.Response = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/suggest").
+        Query("q", "coffee m").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.Response[[]models.ItemSuggestion] = &models.Response[[]models.ItemSuggestion]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the name of the item is suggested
+    if len(response.Data) != 1 || response.Data[0].Name != "Coffee Mug" {
+        t.Fatalf("unexpected suggestions: %+v", response.Data)
+    }
+}
+
+func TestGetItemByBarcode_Success(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a new item with a UPC-A barcode
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Cola", SKU: "COLA-330", Barcodes: []string{"036000291452"}, Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // scan the same barcode as EAN-13 with the leading zero
+    var resp *http.Response = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/by-barcode/0036000291452").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the item with the barcode is returned
+    if response.Data.SKU != "COLA-330" || len(response.Data.Barcodes) != 1 {
+        t.Fatalf("unexpected item: %+v", response.Data)
+    }
+}
+
+func TestCreateItem_DuplicateUPCBarcode(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a new item with a UPC-A barcode
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Cola", Barcodes: []string{"036000291452"}, Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request with the same barcode in the EAN-13 form
+        Post("/api/v1/items").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(&models.ItemRequest{Name: "Diet Cola", Barcodes: []string{"0036000291452"}, Price: 10, Quantity: 5}).
+        // expect the response status code is equals 409
+        Expect(t).
+        Status(http.StatusConflict).
+        End()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+}
+
+func TestCreateItem_InvalidBarcode(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a new item with an invalid check digit
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Cola", Barcodes: []string{"4006381333932"}, Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestGetItemLabel_PNG(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a new item
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Cola", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var response *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // get the label of the item as a PNG image
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/" + response.Data.ID + "/label").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        Header("Content-Type", "image/png").
+        End()
+}
+
+func TestCreateItemLabelSheet_NotFound(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // print the labels of an unknown item
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items/labels").
+        Header("Authorization", token).
+        JSON(&models.LabelSheetRequest{ItemIDs: []string{uuid.New().String()}}).
+        Expect(t).
+        Status(http.StatusNotFound).
+        End()
+}
+
+// createCategory returns a new category created through the API
+func createCategory(t *testing.T, token string, name string, parentID *string) models.Category {
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/categories").
+        Header("Authorization", token).
+        JSON(&models.CategoryRequest{Name: name, ParentID: parentID}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var response *models.Response[models.Category] = &models.Response[models.Category]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    return response.Data
+}
+
+func TestGetAllItems_CategoryFilter(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a category with a subcategory
+    var parent models.Category = createCategory(t, token, "Drinks", nil)
+    var child models.Category = createCategory(t, token, "Soda", &parent.ID)
+
+    // create an item inside the subcategory and an item without category
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Cola", CategoryID: &child.ID, Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Bread", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // list the items of the parent category
+    var resp *http.Response = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items").
+        Query("category_id", parent.ID).
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.PaginatedResponse[[]models.Item] = &models.PaginatedResponse[[]models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // only the item of the subcategory is returned
+    if len(response.Data) != 1 || response.Data[0].Name != "Cola" {
+        t.Fatalf("unexpected items: %+v", response.Data)
+    }
+}
+
+func TestUpdateCategory_Cycle(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a category with a subcategory
+    var parent models.Category = createCategory(t, token, "Drinks", nil)
+    var child models.Category = createCategory(t, token, "Soda", &parent.ID)
+
+    // move the parent category under its subcategory
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Put("/api/v1/categories/" + parent.ID).
+        Header("Authorization", token).
+        JSON(&models.CategoryRequest{Name: "Drinks", ParentID: &child.ID}).
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestDeleteCategory_NotEmpty(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a category with a subcategory
+    var parent models.Category = createCategory(t, token, "Drinks", nil)
+    createCategory(t, token, "Soda", &parent.ID)
+
+    // delete the category that still has a subcategory
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Delete("/api/v1/categories/" + parent.ID).
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusConflict).
+        End()
+}
+
+func TestCreateItem_InvalidAttribute(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a category with a number attribute
+    var category models.Category = createCategory(t, token, "Lamps", nil)
+    var max float64 = 240
+
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/categories/" + category.ID + "/attributes").
+        Header("Authorization", token).
+        JSON(&models.AttributeDefinitionRequest{Name: "voltage", Type: models.AttributeTypeNumber, Required: true, Max: &max}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // create an item with a voltage that is out of the range
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Desk Lamp", CategoryID: &category.ID, Attributes: map[string]any{"voltage": 400}, Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusBadRequest).
+        End()
+}
+
+func TestGetAllItems_TagAndAttributeFilter(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a category with an enum attribute
+    var category models.Category = createCategory(t, token, "Shirts", nil)
+
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/categories/" + category.ID + "/attributes").
+        Header("Authorization", token).
+        JSON(&models.AttributeDefinitionRequest{Name: "color", Type: models.AttributeTypeEnum, Options: []string{"red", "blue"}}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // create two items with different colors and tags
+    for _, itemRequest := range []models.ItemRequest{
+        {Name: "Red Shirt", CategoryID: &category.ID, Tags: []string{"Summer"}, Attributes: map[string]any{"color": "red"}, Price: 10, Quantity: 5},
+        {Name: "Blue Shirt", CategoryID: &category.ID, Tags: []string{"summer"}, Attributes: map[string]any{"color": "blue"}, Price: 10, Quantity: 5},
+    } {
+        apitest.New().
+            HandlerFunc(FiberToHandlerFunc(newApp())).
+            Post("/api/v1/items").
+            Header("Authorization", token).
+            JSON(&itemRequest).
+            Expect(t).
+            Status(http.StatusCreated).
+            End()
+    }
+
+    // list the red items with the tag
+    var resp *http.Response = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items").
+        Query("tags", "summer").
+        Query("attr.color", "red").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.PaginatedResponse[[]models.Item] = &models.PaginatedResponse[[]models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // only the red item is returned
+    if len(response.Data) != 1 || response.Data[0].Name != "Red Shirt" {
+        t.Fatalf("unexpected items: %+v", response.Data)
+    }
+}
+
+func TestGetItemStock_DefaultLocation(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Notebook", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var created *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&created)
+
+    // get the stock of the item
+    resp = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/" + created.Data.ID + "/stock").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.Response[models.ItemStock] = &models.Response[models.ItemStock]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the initial quantity is stocked in the default location
+    if response.Data.Quantity != 5 || len(response.Data.Locations) != 1 || response.Data.Locations[0].LocationCode != "DEFAULT" {
+        t.Fatalf("unexpected stock: %+v", response.Data)
+    }
+}
+
+func TestGetItemStock_ReadOnly(t *testing.T) {
+    // get the sample data for item entity
+    // the seeded item does not have movements yet
+    var item models.Item = getItem()
+
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a GET request to get the stock of the item
+        Get("/api/v1/items/" + item.ID + "/stock").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // reading the stock does not record movements
+    var count int64
+    database.DB.Model(&models.StockMovement{}).Where("item_id = ?", item.ID).Count(&count)
+
+    // clean up the seeded data
+    database.CleanSeeders()
+
+    if count != 0 {
+        t.Fatalf("unexpected movements: %d", count)
+    }
+}
+
+func TestDeleteItem_WritesOffStock(t *testing.T) {
+    // get the sample data for item entity
+    // the seeded item has stock without movements
+    var item models.Item = getItem()
+
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a DELETE request for deleting the item
+        Delete("/api/v1/items/" + item.ID).
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 200
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // the remaining stock is written off in the ledger
+    var movements []models.StockMovement
+    database.DB.Where("item_id = ?", item.ID).Find(&movements)
+
+    var total int
+    var writeOffs int
+    for _, movement := range movements {
+        total += movement.Quantity
+        if movement.ReasonCode == models.ReasonItemDeleted {
+            writeOffs++
+        }
+    }
+
+    // clean up the seeded data
+    database.CleanSeeders()
+
+    if writeOffs != 1 || total != 0 {
+        t.Fatalf("unexpected movements: %+v", movements)
+    }
+}
+
+func TestGenerateVariants_Success(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create the product
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "T-Shirt", SKU: "TSHIRT", Price: 10, Quantity: 0}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var created *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&created)
+
+    // generate the variants of two sizes and two colors
+    resp = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items/" + created.Data.ID + "/variants").
+        Header("Authorization", token).
+        JSON(&models.VariantMatrixRequest{Axes: []models.VariantAxis{
+            {Name: "size", Values: []string{"S", "M"}},
+            {Name: "color", Values: []string{"Red", "Blue"}},
+        }}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var response *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the product is returned with its variants
+    if len(response.Data.Variants) != 4 || response.Data.Variants[0].SKU != "TSHIRT-M-BLUE" {
+        t.Fatalf("unexpected variants: %+v", response.Data.Variants)
+    }
+}
+
+func TestGetItemMovements_Success(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Stapler", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var created *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&created)
+
+    // get the default location of the item
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/" + created.Data.ID + "/stock").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var stock *models.Response[models.ItemStock] = &models.Response[models.ItemStock]{}
+    json.NewDecoder(resp.Body).Decode(&stock)
+
+    // issue two units of the item
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items/" + created.Data.ID + "/movements").
+        Header("Authorization", token).
+        JSON(&models.StockMovementRequest{Type: models.MovementTypeIssue, LocationID: stock.Data.Locations[0].LocationID, Quantity: 2, Reference: "SO-1001"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // list the movements of the item
+    resp = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/" + created.Data.ID + "/movements").
+        Query("from", time.Now().Format("2006-01-02")).
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.Response[[]models.StockMovement] = &models.Response[[]models.StockMovement]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    // the issue is returned before the receipt of the initial quantity
+    if len(response.Data) != 2 || response.Data[0].Quantity != -2 || response.Data[0].Balance != 3 || response.Data[1].Type != models.MovementTypeReceipt {
+        t.Fatalf("unexpected movements: %+v", response.Data)
+    }
+}
+
+func TestCreateItemMovement_InsufficientStock(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Eraser", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var created *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&created)
+
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/" + created.Data.ID + "/stock").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var stock *models.Response[models.ItemStock] = &models.Response[models.ItemStock]{}
+    json.NewDecoder(resp.Body).Decode(&stock)
+
+    // issue more units than the stock of the location
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items/" + created.Data.ID + "/movements").
+        Header("Authorization", token).
+        JSON(&models.StockMovementRequest{Type: models.MovementTypeIssue, LocationID: stock.Data.Locations[0].LocationID, Quantity: 10}).
+        Expect(t).
+        Status(http.StatusConflict).
+        End()
+}
+
+func getItemStock(t *testing.T, token string, itemID string) models.ItemStock {
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/" + itemID + "/stock").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.Response[models.ItemStock] = &models.Response[models.ItemStock]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    return response.Data
+}
+
+func TestReceiveTransferOrder_Discrepancy(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity in the default location
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Pallet", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var item *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&item)
+
+    var fromLocationID string = getItemStock(t, token, item.Data.ID).Locations[0].LocationID
+
+    // create another warehouse with a location
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/warehouses").
+        Header("Authorization", token).
+        JSON(&models.WarehouseRequest{Code: "EAST", Name: "East warehouse"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var warehouse *models.Response[models.Warehouse] = &models.Response[models.Warehouse]{}
+    json.NewDecoder(resp.Body).Decode(&warehouse)
+
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/warehouses/" + warehouse.Data.ID + "/locations").
+        Header("Authorization", token).
+        JSON(&models.LocationRequest{Code: "A-01"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var location *models.Response[models.Location] = &models.Response[models.Location]{}
+    json.NewDecoder(resp.Body).Decode(&location)
+
+    // create and ship the transfer of every unit
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/transfers").
+        Header("Authorization", token).
+        JSON(&models.TransferOrderRequest{
+            FromLocationID: fromLocationID,
+            ToLocationID:   location.Data.ID,
+            Lines:          []models.TransferLineRequest{{ItemID: item.Data.ID, Quantity: 5}},
+        }).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var transfer *models.Response[models.TransferOrder] = &models.Response[models.TransferOrder]{}
+    json.NewDecoder(resp.Body).Decode(&transfer)
+
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/transfers/" + transfer.Data.ID + "/ship").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // the shipped units are in transit
+    if stock := getItemStock(t, token, item.Data.ID); stock.Quantity != 0 || stock.InTransit != 5 {
+        t.Fatalf("unexpected stock after shipment: %+v", stock)
+    }
+
+    // receive three units and complete the transfer
+    resp = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/transfers/" + transfer.Data.ID + "/receive").
+        Header("Authorization", token).
+        JSON(&models.TransferReceiptRequest{
+            Lines:    []models.TransferLineRequest{{ItemID: item.Data.ID, Quantity: 3}},
+            Complete: true,
+        }).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    json.NewDecoder(resp.Body).Decode(&transfer)
+
+    // the missing units are reported as the discrepancy
+    if transfer.Data.Status != models.TransferStatusReceived || transfer.Data.Lines[0].Discrepancy != 2 {
+        t.Fatalf("unexpected transfer: %+v", transfer.Data)
+    }
+}
+
+func TestDeleteItem_InTransit(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity in the default location
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Crate", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var item *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&item)
+
+    var fromLocationID string = getItemStock(t, token, item.Data.ID).Locations[0].LocationID
+
+    // create another warehouse with a location
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/warehouses").
+        Header("Authorization", token).
+        JSON(&models.WarehouseRequest{Code: "WEST", Name: "West warehouse"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var warehouse *models.Response[models.Warehouse] = &models.Response[models.Warehouse]{}
+    json.NewDecoder(resp.Body).Decode(&warehouse)
+
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/warehouses/" + warehouse.Data.ID + "/locations").
+        Header("Authorization", token).
+        JSON(&models.LocationRequest{Code: "B-01"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var location *models.Response[models.Location] = &models.Response[models.Location]{}
+    json.NewDecoder(resp.Body).Decode(&location)
+
+    // create and ship the transfer of two units
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/transfers").
+        Header("Authorization", token).
+        JSON(&models.TransferOrderRequest{
+            FromLocationID: fromLocationID,
+            ToLocationID:   location.Data.ID,
+            Lines:          []models.TransferLineRequest{{ItemID: item.Data.ID, Quantity: 2}},
+        }).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var transfer *models.Response[models.TransferOrder] = &models.Response[models.TransferOrder]{}
+    json.NewDecoder(resp.Body).Decode(&transfer)
+
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/transfers/" + transfer.Data.ID + "/ship").
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a DELETE request for deleting the item in transit
+        Delete("/api/v1/items/" + item.Data.ID).
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 409
+        Expect(t).
+        Status(http.StatusConflict).
+        End()
+}
+
+func TestCreateReservation_Availability(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Printer", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var created *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&created)
+
+    // reserve three units of the item
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/reservations").
+        Header("Authorization", token).
+        JSON(&models.ReservationRequest{ItemID: created.Data.ID, Quantity: 3, Reference: "SO-2001"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // the reservation cannot exceed the available units
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/reservations").
+        Header("Authorization", token).
+        JSON(&models.ReservationRequest{ItemID: created.Data.ID, Quantity: 3, Reference: "SO-2002"}).
+        Expect(t).
+        Status(http.StatusConflict).
+        End()
+
+    // get the item with its quantities
+    resp = apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Get("/api/v1/items/" + created.Data.ID).
+        Header("Authorization", token).
+        Expect(t).
+        Status(http.StatusOK).
+        End().Response
+
+    var response *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&response)
+
+    if response.Data.OnHand != 5 || response.Data.Reserved != 3 || response.Data.Available != 2 {
+        t.Fatalf("unexpected quantities: on hand %d, reserved %d, available %d", response.Data.OnHand, response.Data.Reserved, response.Data.Available)
+    }
+}
+
+func TestUpdateItem_QuantityInManyLocations(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity in the default location
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Ladder", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var created *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&created)
+
+    var fromLocationID string = getItemStock(t, token, created.Data.ID).Locations[0].LocationID
+
+    // create another warehouse with a location
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/warehouses").
+        Header("Authorization", token).
+        JSON(&models.WarehouseRequest{Code: "NORTH", Name: "North warehouse"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var warehouse *models.Response[models.Warehouse] = &models.Response[models.Warehouse]{}
+    json.NewDecoder(resp.Body).Decode(&warehouse)
+
+    resp = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/warehouses/" + warehouse.Data.ID + "/locations").
+        Header("Authorization", token).
+        JSON(&models.LocationRequest{Code: "C-01"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var location *models.Response[models.Location] = &models.Response[models.Location]{}
+    json.NewDecoder(resp.Body).Decode(&location)
+
+    // move two units into the other location
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items/" + created.Data.ID + "/movements").
+        Header("Authorization", token).
+        JSON(&models.StockMovementRequest{Type: models.MovementTypeTransfer, LocationID: fromLocationID, ToLocationID: location.Data.ID, Quantity: 2}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a PUT request to change the quantity of the item
+        Put("/api/v1/items/" + created.Data.ID).
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(&models.ItemRequest{Name: "Ladder", SKU: created.Data.SKU, Price: 10, Quantity: 10}).
+        // expect the response status code is equals 409
+        Expect(t).
+        Status(http.StatusConflict).
+        End()
+}
+
+func TestCreateItemMovement_ReservedStock(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Scanner", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var created *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&created)
+
+    // reserve three units of the item
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/reservations").
+        Header("Authorization", token).
+        JSON(&models.ReservationRequest{ItemID: created.Data.ID, Quantity: 3, Reference: "SO-3001"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // get the default location of the item
+    var stock models.ItemStock = getItemStock(t, token, created.Data.ID)
+
+    // create a test
+    apitest.New().
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a POST request to issue the reserved units
+        Post("/api/v1/items/" + created.Data.ID + "/movements").
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // set the request body
+        JSON(&models.StockMovementRequest{Type: models.MovementTypeIssue, LocationID: stock.Locations[0].LocationID, Quantity: 3}).
+        // expect the response status code is equals 409
+        Expect(t).
+        Status(http.StatusConflict).
+        End()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+}
+
+func TestDeleteItem_Reserved(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Printer", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var created *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&created)
+
+    // reserve two units of the item
+    apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/reservations").
+        Header("Authorization", token).
+        JSON(&models.ReservationRequest{ItemID: created.Data.ID, Quantity: 2, Reference: "SO-4001"}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End()
+
+    // create a test
+    apitest.New().
+        // run the cleanup() function after the test is finished
+        Observe(cleanup).
+        // add an application to be tested
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        // send a DELETE request for deleting the reserved item
+        Delete("/api/v1/items/" + created.Data.ID).
+        // attach the JWT token into Authorization header
+        Header("Authorization", token).
+        // expect the response status code is equals 409
+        Expect(t).
+        Status(http.StatusConflict).
+        End()
+}
+
+func TestCreateReservation_Concurrent(t *testing.T) {
+    // get the JWT token for authentication
+    var token string = getJWTToken(t)
+
+    // create an item with an initial quantity
+    var resp *http.Response = apitest.New().
+        HandlerFunc(FiberToHandlerFunc(newApp())).
+        Post("/api/v1/items").
+        Header("Authorization", token).
+        JSON(&models.ItemRequest{Name: "Monitor", Price: 10, Quantity: 5}).
+        Expect(t).
+        Status(http.StatusCreated).
+        End().Response
+
+    var created *models.Response[models.Item] = &models.Response[models.Item]{}
+    json.NewDecoder(resp.Body).Decode(&created)
+
+    // reserve three units of the item twice at the same time
+    var handler http.HandlerFunc = FiberToHandlerFunc(newApp())
+    var statuses []int = make([]int, 2)
+    var wg sync.WaitGroup
+
+    for i := range statuses {
+        wg.Add(1)
+
+        go func(i int) {
+            defer wg.Done()
+
+            body, _ := json.Marshal(&models.ReservationRequest{ItemID: created.Data.ID, Quantity: 3})
+            req := httptest.NewRequest(http.MethodPost, "/api/v1/reservations", bytes.NewReader(body))
+            req.Header.Set("Content-Type", "application/json")
+            req.Header.Set("Authorization", token)
+
+            recorder := httptest.NewRecorder()
+            handler(recorder, req)
+            statuses[i] = recorder.Code
+        }(i)
+    }
+
+    wg.Wait()
+
+    // clean up the seeded data
+    database.CleanSeeders()
+
+    // only one of the reservations can hold the stock
+    if !(statuses[0] == http.StatusCreated && statuses[1] == http.StatusConflict) && !(statuses[0] == http.StatusConflict && statuses[1] == http.StatusCreated) {
+        t.Fatalf("unexpected statuses: %v", statuses)
+    }
+}
@@ -1,122 +1,212 @@
-package database
-
-import (
-	"fmt"
-	"errors"
-	
-	"inventory-project-testing/models"
-	"inventory-project-testing/utils"
-
-	"golang.org/x/crypto/bcrypt"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
-)
-
-// create a variable to store the database instance
-var DB *gorm.DB
-
-// InitDatabase creates a connection to the database
-func InitDatabase(dbName string) {
-
-    // initialize some variables
-    // for the MySQL data source
-	var (
-		databaseUser     string = utils.GetValue("DB_USER")
-		databasePassword string = utils.GetValue("DB_PASSWORD")
-		databaseHost     string = utils.GetValue("DB_HOST")
-		databasePort     string = utils.GetValue("DB_PORT")
-		databaseName     string = dbName
-	)
-
-    // declare the data source for MySQL
-	var dataSource string = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", databaseUser, databasePassword, databaseHost, databasePort, databaseName)
-
-    // create a variable to store an error
-	var err error
-
-    // create a connection to the database
-	DB, err = gorm.Open(mysql.Open(dataSource), &gorm.Config{})
-
-    // if connection fails, print out the errors
-	if err != nil {
-		panic(err.Error())
-	}
-
-    // if connection is successful, print out this message
-	fmt.Println("Connected to the database")
-
-	DB.AutoMigrate(&models.User{}, &models.Item{})
-}
-
-
-//SeedItem returns recently created items from the database
-func SeedItem()(models.Item, error){
-	//create a sample data for item 
-	item, err := utils.CreateFaker[models.Item]()
-	if err != nil{
-		return models.Item{}, nil
-	}
-
-	//insert the sample data into the database 
-	DB.Create(&item)
-	fmt.Println("Item seeded to the database")
-
-	//return recently created item
-	return item, nil
-}
-
-
-//SeedUser returns recently created user from the database 
-func SeedUser()(models.User, error){
-	//create a sample data for user
-	user, err := utils.CreateFaker[models.User]()
-	if err != nil{
-		return models.User{}, err
-	}
-
-	//create a password with bcrypt 
-	//this password is stored to the database
-	password, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
-
-	if err != nil{
-		return models.User{}, err
-	}
-
-	//create a variable called "inputUser"
-	//this variable is used to store the user sample data
-	//into the database
-
-	var inputUser models.User = models.User{
-		ID: user.ID,
-		Email: user.Email,
-		Password: string(password),
-	}
-
-	//insert the user sample data into the database 
-	DB.Create(&inputUser)
-	fmt.Println("User seeded to the database")
-
-	//return the user sample data
-	return user,nil
-}
-
-// CleanSeeders performs clean up mechanism after testing
-func CleanSeeders() {
-    // remove all data inside items table
-    itemResult := DB.Exec("TRUNCATE items")
-    // remove all data inside users table
-    userResult := DB.Exec("TRUNCATE users")
-
-
-    // check if the operation is failed
-    var isFailed bool = itemResult.Error != nil || userResult.Error != nil
-
-
-    // if operation is failed, return an error
-    if isFailed {
-        panic(errors.New("error when cleaning up seeders"))
-    }
-
-
-    fmt.Println("Seeders are cleaned up successfully")
+package database
+
+import (
+	"fmt"
+	"errors"
+	"time"
+	
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// create a variable to store the database instance
+var DB *gorm.DB
+
+// InitDatabase creates a connection to the database
+func InitDatabase(dbName string) {
+
+    // initialize some variables
+    // for the MySQL data source
+	var (
+		databaseUser     string = utils.GetValue("DB_USER")
+		databasePassword string = utils.GetValue("DB_PASSWORD")
+		databaseHost     string = utils.GetValue("DB_HOST")
+		databasePort     string = utils.GetValue("DB_PORT")
+		databaseName     string = dbName
+	)
+
+    // declare the data source for MySQL
+	var dataSource string = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", databaseUser, databasePassword, databaseHost, databasePort, databaseName)
+
+    // create a variable to store an error
+	var err error
+
+    // create a connection to the database
+	DB, err = gorm.Open(mysql.Open(dataSource), &gorm.Config{
+		// the duplicate key errors are returned as gorm.ErrDuplicatedKey
+		TranslateError: true,
+	})
+
+    // if connection fails, print out the errors
+	if err != nil {
+		panic(err.Error())
+	}
+
+    // if connection is successful, print out this message
+	fmt.Println("Connected to the database")
+
+	//find the columns of the existing users that are added by the migration
+	var missingUserColumns map[string]bool = getMissingUserColumns()
+
+	DB.AutoMigrate(&models.User{}, &models.Item{}, &models.RefreshToken{}, &models.RevokedToken{}, &models.SigningKey{}, &models.APIKey{}, &models.UserToken{}, &models.LoginThrottle{}, &models.LoginEvent{}, &models.RecoveryCode{}, &models.Organization{}, &models.Membership{}, &models.Invitation{}, &models.Identity{}, &models.OIDCState{}, &models.ItemBarcode{}, &models.Category{}, &models.AttributeDefinition{}, &models.Warehouse{}, &models.Location{}, &models.StockLevel{}, &models.StockMovement{}, &models.TransferOrder{}, &models.TransferLine{}, &models.Reservation{}, &models.DataMigration{})
+
+	//migrate the data that existed before the new features
+	if err := migrateExistingUsers(missingUserColumns); err != nil {
+		panic(err.Error())
+	}
+
+	if err := runDataMigrations(); err != nil {
+		panic(err.Error())
+	}
+}
+
+
+//SeedOrganization returns the organization that is shared by the seeded data
+func SeedOrganization()(models.Organization, error){
+	var organization models.Organization
+
+	//create the organization if it is not seeded yet
+	result := DB.Where(models.Organization{Slug: "seed"}).
+		Attrs(models.Organization{ID: uuid.New().String(), Name: "Seed Organization"}).
+		FirstOrCreate(&organization)
+
+	return organization, result.Error
+}
+
+//SeedItem returns recently created items from the database
+func SeedItem()(models.Item, error){
+	//create a sample data for item 
+	item, err := utils.CreateFaker[models.Item]()
+	if err != nil{
+		return models.Item{}, nil
+	}
+
+	//the item belongs to the seeded organization
+	organization, err := SeedOrganization()
+	if err != nil{
+		return models.Item{}, err
+	}
+
+	item.OrganizationID = organization.ID
+
+	//insert the sample data into the database 
+	DB.Create(&item)
+	fmt.Println("Item seeded to the database")
+
+	//return recently created item
+	return item, nil
+}
+
+
+//SeedUser returns recently created user with the manager role from the database 
+func SeedUser()(models.User, error){
+	return SeedUserWithRole(models.RoleManager)
+}
+
+//SeedUserWithRole returns recently created user with the given role from the database 
+func SeedUserWithRole(role string)(models.User, error){
+	//create a sample data for user
+	user, err := utils.CreateFaker[models.User]()
+	if err != nil{
+		return models.User{}, err
+	}
+
+	//create a password with bcrypt 
+	//this password is stored to the database
+	password, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+
+	if err != nil{
+		return models.User{}, err
+	}
+
+	//the email of the seeded user is already verified
+	var now time.Time = time.Now()
+
+	//create a variable called "inputUser"
+	//this variable is used to store the user sample data
+	//into the database
+
+	var inputUser models.User = models.User{
+		ID: user.ID,
+		Email: user.Email,
+		Password: string(password),
+		Role: role,
+		EmailVerifiedAt: &now,
+	}
+
+	//insert the user sample data into the database 
+	DB.Create(&inputUser)
+	fmt.Println("User seeded to the database")
+
+	//the user is a member of the seeded organization with the same role
+	organization, err := SeedOrganization()
+	if err != nil{
+		return models.User{}, err
+	}
+
+	DB.Create(&models.Membership{
+		ID: uuid.New().String(),
+		OrganizationID: organization.ID,
+		UserID: user.ID,
+		Role: role,
+	})
+
+	//return the user sample data
+	user.Role = role
+	user.EmailVerifiedAt = &now
+	return user,nil
+}
+
+// seededTables contains the tables that are cleaned up after testing
+var seededTables []string = []string{
+    "items",
+    "users",
+    "refresh_tokens",
+    "revoked_tokens",
+    "api_keys",
+    "user_tokens",
+    "login_throttles",
+    "login_events",
+    "recovery_codes",
+    "organizations",
+    "memberships",
+    "invitations",
+    "identities",
+    "oidc_states",
+    "item_barcodes",
+    "categories",
+    "attribute_definitions",
+    "warehouses",
+    "locations",
+    "stock_levels",
+    "stock_movements",
+    "transfer_orders",
+    "transfer_lines",
+    "reservations",
+}
+
+// CleanSeeders performs clean up mechanism after testing
+func CleanSeeders() {
+    // check if the operation is failed
+    var isFailed bool
+
+    // remove all data inside every seeded table
+    for _, table := range seededTables {
+        result := DB.Exec("TRUNCATE " + table)
+        isFailed = isFailed || result.Error != nil
+    }
+
+
+    // if operation is failed, return an error
+    if isFailed {
+        panic(errors.New("error when cleaning up seeders"))
+    }
+
+
+    fmt.Println("Seeders are cleaned up successfully")
 }
\ No newline at end of file
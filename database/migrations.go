@@ -0,0 +1,146 @@
+package database
+
+import (
+	"time"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//dataMigration changes the data that existed before a new feature was deployed
+type dataMigration struct {
+	ID      string
+	Migrate func(tx *gorm.DB) error
+}
+
+//dataMigrations contains the one-time migrations of the existing data in the order they are run
+var dataMigrations []dataMigration = []dataMigration{
+	{ID: "default_organization", Migrate: migrateDefaultOrganization},
+	{ID: "backfill_item_skus", Migrate: backfillItemSKUs},
+}
+
+//runDataMigrations runs the data migrations that are not recorded yet
+//every migration is recorded in the same transaction, so it is only run once
+func runDataMigrations() error {
+	for _, migration := range dataMigrations {
+		var count int64
+		DB.Model(&models.DataMigration{}).Where("id = ?", migration.ID).Count(&count)
+
+		if count > 0 {
+			continue
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Migrate(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&models.DataMigration{ID: migration.ID}).Error
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//getMissingUserColumns returns the columns of the users table that do not exist yet
+//the users that exist before a column is added were created before the feature of the column
+func getMissingUserColumns() map[string]bool {
+	var missingColumns map[string]bool = map[string]bool{}
+
+	if !DB.Migrator().HasTable(&models.User{}) {
+		return missingColumns
+	}
+
+	for _, column := range []string{"role", "email_verified_at"} {
+		missingColumns[column] = !DB.Migrator().HasColumn(&models.User{}, column)
+	}
+
+	return missingColumns
+}
+
+//migrateExistingUsers keeps the access of the users that existed before the roles and the email verification
+//it is run after the missing columns are added, so it is only run once
+func migrateExistingUsers(missingColumns map[string]bool) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		//the existing users cannot be blocked by the email verification
+		if missingColumns["email_verified_at"] {
+			if err := tx.Model(&models.User{}).Where("email_verified_at IS NULL").Update("email_verified_at", time.Now()).Error; err != nil {
+				return err
+			}
+		}
+
+		//every user could manage the items before the roles existed
+		if missingColumns["role"] {
+			if err := tx.Model(&models.User{}).Where("1 = 1").Update("role", models.RoleManager).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+//migrateDefaultOrganization moves the items and the users that existed before the organizations into the default organization
+//the users become members with their own role, so they keep the access they had before
+func migrateDefaultOrganization(tx *gorm.DB) error {
+	var users []models.User
+	tx.Where("id NOT IN (SELECT user_id FROM memberships)").Find(&users)
+
+	var itemsCount int64
+	tx.Model(&models.Item{}).Where("organization_id = '' OR organization_id IS NULL").Count(&itemsCount)
+
+	//if there is nothing to migrate, the default organization is not created
+	if len(users) == 0 && itemsCount == 0 {
+		return nil
+	}
+
+	var organization models.Organization
+	result := tx.Where(models.Organization{Slug: "default"}).
+		Attrs(models.Organization{ID: uuid.New().String(), Name: "Default Organization"}).
+		FirstOrCreate(&organization)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	//move the items and the API keys without organization into the default organization
+	for _, model := range []any{&models.Item{}, &models.APIKey{}} {
+		if err := tx.Model(model).Where("organization_id = '' OR organization_id IS NULL").Update("organization_id", organization.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, user := range users {
+		if err := tx.Create(&models.Membership{
+			ID:             uuid.New().String(),
+			OrganizationID: organization.ID,
+			UserID:         user.ID,
+			Role:           user.Role,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//backfillItemSKUs generates the SKU of every item that was created before the SKUs existed
+func backfillItemSKUs(tx *gorm.DB) error {
+	var itemIDs []string
+	tx.Model(&models.Item{}).Where("sku IS NULL OR sku = ''").Pluck("id", &itemIDs)
+
+	for _, itemID := range itemIDs {
+		if err := tx.Model(&models.Item{}).Where("id = ?", itemID).Update("sku", utils.GenerateSKU()).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
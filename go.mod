@@ -3,7 +3,9 @@ module inventory-project-testing
 go 1.21.0
 
 require (
+	github.com/boombuler/barcode v1.0.1
 	github.com/bxcodec/faker/v3 v3.8.1
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/go-playground/validator/v10 v10.15.1
 	github.com/gofiber/fiber/v2 v2.48.0
 	github.com/gofiber/jwt/v3 v3.3.10
@@ -12,6 +14,7 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/steinfletcher/apitest v1.5.15
 	golang.org/x/crypto v0.12.0
+	golang.org/x/image v0.18.0
 	gorm.io/driver/mysql v1.5.1
 	gorm.io/gorm v1.25.4
 )
@@ -36,5 +39,5 @@ require (
 	github.com/valyala/tcplisten v1.0.0 // indirect
 	golang.org/x/net v0.10.0 // indirect
 	golang.org/x/sys v0.11.0 // indirect
-	golang.org/x/text v0.12.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
 )
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CreateAPIKey(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if _, ok := utils.GetCurrentAPIKey(c); ok {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: "api keys cannot be managed with an api key",
+		})
+	}
+
+	var apiKeyInput *models.APIKeyRequest = new(models.APIKeyRequest)
+
+	if err := c.BodyParser(apiKeyInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	errors := apiKeyInput.ValidateStruct()
+
+	if errors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    errors,
+		})
+	}
+
+	metadata, err := utils.GetCurrentTokenMetadata(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	apiKey, err := services.CreateAPIKey(user, metadata.OrganizationID, *apiKeyInput)
+
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.CreatedAPIKey]{
+		Success: true,
+		Message: "api key created, the key is only shown once",
+		Data:    apiKey,
+	})
+}
+
+func GetAPIKeys(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var apiKeys []models.APIKey = services.GetAPIKeys(user.ID)
+
+	return c.JSON(models.Response[[]models.APIKey]{
+		Success: true,
+		Message: "All api keys data",
+		Data:    apiKeys,
+	})
+}
+
+func RevokeAPIKey(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if _, ok := utils.GetCurrentAPIKey(c); ok {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: "api keys cannot be managed with an api key",
+		})
+	}
+
+	var apiKeyID string = c.Params("id")
+
+	if err := services.RevokeAPIKey(user.ID, apiKeyID); err != nil {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "api key revoked",
+	})
+}
@@ -1,82 +1,236 @@
-package handlers
-
-import (
-	"net/http"
-
-	"inventory-project-testing/models"
-	"inventory-project-testing/services"
-
-	"github.com/gofiber/fiber/v2"
-)
-
-func Signup(c *fiber.Ctx) error {
-	var userInput *models.UserRequest = new(models.UserRequest)
-
-	if err := c.BodyParser(userInput); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	errors := userInput.ValidateStruct()
-
-	if errors != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
-			Success: false,
-			Message: "validation failed",
-			Data:    errors,
-		})
-	}
-
-	token, err := services.Signup(*userInput)
-
-	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	return c.JSON(models.Response[string]{
-		Success: true,
-		Message: "token data",
-		Data:    token,
-	})
-}
-
-func Login(c *fiber.Ctx) error {
-	var userInput *models.UserRequest = new(models.UserRequest)
-
-	if err := c.BodyParser(userInput); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	errors := userInput.ValidateStruct()
-
-	if errors != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
-			Success: false,
-			Message: "validation failed",
-			Data:    errors,
-		})
-	}
-
-	token, err := services.Login(*userInput)
-
-	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	return c.JSON(models.Response[string]{
-		Success: true,
-		Message: "token data",
-		Data:    token,
-	})
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Signup(c *fiber.Ctx) error {
+	var userInput *models.UserRequest = new(models.UserRequest)
+
+	if err := c.BodyParser(userInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := userInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	token, err := services.Signup(*userInput)
+
+	if errors.Is(err, services.ErrSignupDisabled) {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.TokenPair]{
+		Success: true,
+		Message: "token data",
+		Data:    token,
+	})
+}
+
+func Login(c *fiber.Ctx) error {
+	var userInput *models.UserRequest = new(models.UserRequest)
+
+	if err := c.BodyParser(userInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := userInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	token, err := services.Login(*userInput, c.IP())
+
+	var blockedError services.LoginBlockedError
+
+	if errors.As(err, &blockedError) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(blockedError.RetryAfter.Seconds()))))
+		return c.Status(http.StatusTooManyRequests).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, services.ErrInvalidCredentials) {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, services.ErrAccountDisabled) {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if token.MFARequired {
+		return c.JSON(models.Response[models.LoginResult]{
+			Success: true,
+			Message: "mfa required",
+			Data:    token,
+		})
+	}
+
+	return c.JSON(models.Response[models.LoginResult]{
+		Success: true,
+		Message: "token data",
+		Data:    token,
+	})
+}
+
+func RefreshToken(c *fiber.Ctx) error {
+	var refreshInput *models.RefreshRequest = new(models.RefreshRequest)
+
+	if err := c.BodyParser(refreshInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	errors := refreshInput.ValidateStruct()
+
+	if errors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    errors,
+		})
+	}
+
+	token, err := services.RefreshToken(*refreshInput)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.TokenPair]{
+		Success: true,
+		Message: "token data",
+		Data:    token,
+	})
+}
+
+func Logout(c *fiber.Ctx) error {
+	metadata, err := utils.GetCurrentTokenMetadata(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := services.Logout(metadata); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "logged out",
+	})
+}
+
+func RevokeAllSessions(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var userID string = c.Params("id")
+
+	if user.ID != userID && !utils.HasPermission(c, models.PermissionUsersManage) {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: "permission denied",
+		})
+	}
+
+	if _, err := services.GetUserByID(userID); err != nil {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := services.RevokeAllSessions(userID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "all sessions revoked",
+	})
+}
+
+func GetJWKS(c *fiber.Ctx) error {
+	keySet, err := services.GetJWKS()
+
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(keySet)
 }
\ No newline at end of file
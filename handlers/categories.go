@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetCategories(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var categories []models.Category = services.GetCategoryTree(organizationID)
+
+	return c.JSON(models.Response[[]models.Category]{
+		Success: true,
+		Message: "All categories data",
+		Data:    categories,
+	})
+}
+
+func GetCategoryByID(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	category, err := services.GetCategoryByID(organizationID, c.Params("id"))
+
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.Category]{
+		Success: true,
+		Message: "category found",
+		Data:    category,
+	})
+}
+
+func CreateCategory(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var categoryInput *models.CategoryRequest = new(models.CategoryRequest)
+
+	if err := c.BodyParser(categoryInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := categoryInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	category, err := services.CreateCategory(organizationID, *categoryInput)
+
+	if err != nil {
+		return sendCategoryError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.Category]{
+		Success: true,
+		Message: "category created",
+		Data:    category,
+	})
+}
+
+func UpdateCategory(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var categoryInput *models.CategoryRequest = new(models.CategoryRequest)
+
+	if err := c.BodyParser(categoryInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := categoryInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	category, err := services.UpdateCategory(organizationID, c.Params("id"), *categoryInput)
+
+	if err != nil {
+		return sendCategoryError(c, err)
+	}
+
+	return c.JSON(models.Response[models.Category]{
+		Success: true,
+		Message: "category updated",
+		Data:    category,
+	})
+}
+
+func DeleteCategory(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := services.DeleteCategory(organizationID, c.Params("id")); err != nil {
+		return sendCategoryError(c, err)
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "category deleted",
+	})
+}
+
+func GetCategoryAttributes(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	definitions, err := services.GetCategoryAttributes(organizationID, c.Params("id"))
+
+	if err != nil {
+		return sendCategoryError(c, err)
+	}
+
+	return c.JSON(models.Response[[]models.AttributeDefinition]{
+		Success: true,
+		Message: "All attributes data",
+		Data:    definitions,
+	})
+}
+
+func CreateAttributeDefinition(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var attributeInput *models.AttributeDefinitionRequest = new(models.AttributeDefinitionRequest)
+
+	if err := c.BodyParser(attributeInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := attributeInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	definition, err := services.CreateAttributeDefinition(organizationID, c.Params("id"), *attributeInput)
+
+	if err != nil {
+		return sendCategoryError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.AttributeDefinition]{
+		Success: true,
+		Message: "attribute created",
+		Data:    definition,
+	})
+}
+
+func UpdateAttributeDefinition(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var attributeInput *models.AttributeDefinitionRequest = new(models.AttributeDefinitionRequest)
+
+	if err := c.BodyParser(attributeInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := attributeInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	definition, err := services.UpdateAttributeDefinition(organizationID, c.Params("id"), c.Params("attributeId"), *attributeInput)
+
+	if err != nil {
+		return sendCategoryError(c, err)
+	}
+
+	return c.JSON(models.Response[models.AttributeDefinition]{
+		Success: true,
+		Message: "attribute updated",
+		Data:    definition,
+	})
+}
+
+func DeleteAttributeDefinition(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := services.DeleteAttributeDefinition(organizationID, c.Params("id"), c.Params("attributeId")); err != nil {
+		return sendCategoryError(c, err)
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "attribute deleted",
+	})
+}
+
+func sendCategoryError(c *fiber.Ctx, err error) error {
+	var status int = http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound), errors.Is(err, services.ErrAttributeNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrCategoryCycle):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrCategoryNotEmpty), errors.Is(err, services.ErrDuplicateCategory), errors.Is(err, services.ErrDuplicateAttribute):
+		status = http.StatusConflict
+	}
+
+	return c.Status(status).JSON(models.Response[any]{
+		Success: false,
+		Message: err.Error(),
+	})
+}
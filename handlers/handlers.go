@@ -1,149 +1,399 @@
-package handlers
-
-import (
-	"net/http"
-	
-	"github.com/gofiber/fiber/v2"
-	"inventory-project-testing/models"
-	"inventory-project-testing/services"
-	"inventory-project-testing/utils"
-)
-
-func GetAllItems(c *fiber.Ctx) error {
-	var items []models.Item = services.GetAllItems()
-
-	return c.JSON(models.Response[[]models.Item]{
-		Success: true,
-		Message: "All items data",
-		Data:    items,
-	})
-}
-
-func GetItemByID(c *fiber.Ctx) error {
-	var itemID string = c.Params("id")
-
-	item, err := services.GetItemByID(itemID)
-	if err != nil {
-		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	return c.JSON(models.Response[models.Item]{
-		Success: true,
-		Message: "item found",
-		Data:    item,
-	})
-}
-
-func CreateItem(c *fiber.Ctx) error {
-	isValid, err := utils.CheckToken(c)
-
-	if !isValid {
-		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	var itemInput *models.ItemRequest = new(models.ItemRequest)
-
-	if err := c.BodyParser(itemInput); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	errors := itemInput.ValidateStruct()
-
-	if errors != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
-			Success: false,
-			Message: "validation failed",
-			Data:    errors,
-		})
-	}
-
-	var createdItem models.Item = services.CreateItem(*itemInput)
-
-	return c.Status(http.StatusCreated).JSON(models.Response[models.Item]{
-		Success: true,
-		Message: "item created",
-		Data:    createdItem,
-	})
-}
-
-func UpdateItem(c *fiber.Ctx) error {
-	isValid, err := utils.CheckToken(c)
-
-	if !isValid {
-		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	var itemInput *models.ItemRequest = new(models.ItemRequest)
-
-	if err := c.BodyParser(itemInput); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	errors := itemInput.ValidateStruct()
-
-	if errors != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
-			Success: false,
-			Message: "validation failed",
-			Data:    errors,
-		})
-	}
-
-	var itemID string = c.Params("id")
-
-	updatedItem, err := services.UpdateItem(*itemInput, itemID)
-	if err != nil {
-		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	return c.JSON(models.Response[models.Item]{
-		Success: true,
-		Message: "item updated",
-		Data:    updatedItem,
-	})
-}
-
-func DeleteItem(c *fiber.Ctx) error {
-	isValid, err := utils.CheckToken(c)
-
-	if !isValid {
-		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
-			Success: false,
-			Message: err.Error(),
-		})
-	}
-
-	var itemID string = c.Params("id")
-
-	var result bool = services.DeleteItem(itemID)
-
-	if result {
-		return c.JSON(models.Response[any]{
-			Success: true,
-			Message: "item deleted",
-		})
-	}
-
-	return c.Status(http.StatusNotFound).JSON(models.Response[any]{
-		Success: false,
-		Message: "item failed to delete",
-	})
-}
\ No newline at end of file
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+)
+
+func GetAllItems(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var itemQuery *models.ItemQuery = new(models.ItemQuery)
+
+	if err := c.QueryParser(itemQuery); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	itemQuery.AttributeFilters = models.ParseAttributeFilters(c.Queries())
+
+	validationErrors := itemQuery.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	items, pagination, err := services.GetAllItems(organizationID, *itemQuery)
+
+	if errors.Is(err, services.ErrInvalidCursor) || errors.Is(err, services.ErrCategoryNotFound) {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.PaginatedResponse[[]models.Item]{
+		Response: models.Response[[]models.Item]{
+			Success: true,
+			Message: "All items data",
+			Data:    items,
+		},
+		Pagination: pagination,
+	})
+}
+
+func GetItemByID(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var itemID string = c.Params("id")
+
+	item, err := services.GetItemByID(organizationID, itemID)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.Item]{
+		Success: true,
+		Message: "item found",
+		Data:    item,
+	})
+}
+
+func CreateItem(c *fiber.Ctx) error {
+	isValid, err := utils.CheckToken(c)
+
+	if !isValid {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var itemInput *models.ItemRequest = new(models.ItemRequest)
+
+	if err := c.BodyParser(itemInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// the attributes are validated with the attributes of the category
+	var definitions []models.AttributeDefinition = services.GetAttributeDefinitions(organizationID, itemInput.CategoryID)
+
+	validationErrors := itemInput.ValidateStruct(definitions...)
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	createdItem, err := services.CreateItem(organizationID, user.ID, *itemInput)
+
+	if errors.Is(err, services.ErrDuplicateSKU) || errors.Is(err, services.ErrDuplicateBarcode) {
+		return c.Status(http.StatusConflict).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, services.ErrCategoryNotFound) {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.Item]{
+		Success: true,
+		Message: "item created",
+		Data:    createdItem,
+	})
+}
+
+func UpdateItem(c *fiber.Ctx) error {
+	isValid, err := utils.CheckToken(c)
+
+	if !isValid {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var itemInput *models.ItemRequest = new(models.ItemRequest)
+
+	if err := c.BodyParser(itemInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// the attributes are validated with the attributes of the category
+	var definitions []models.AttributeDefinition = services.GetAttributeDefinitions(organizationID, itemInput.CategoryID)
+
+	validationErrors := itemInput.ValidateStruct(definitions...)
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	var itemID string = c.Params("id")
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	updatedItem, err := services.UpdateItem(organizationID, user.ID, *itemInput, itemID)
+	if errors.Is(err, services.ErrDuplicateSKU) || errors.Is(err, services.ErrDuplicateBarcode) {
+		return c.Status(http.StatusConflict).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, services.ErrInsufficientStock) || errors.Is(err, services.ErrStockReserved) || errors.Is(err, services.ErrQuantityByLocation) {
+		return c.Status(http.StatusConflict).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, services.ErrCategoryNotFound) {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.Item]{
+		Success: true,
+		Message: "item updated",
+		Data:    updatedItem,
+	})
+}
+
+func DeleteItem(c *fiber.Ctx) error {
+	isValid, err := utils.CheckToken(c)
+
+	if !isValid {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var itemID string = c.Params("id")
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	err = services.DeleteItem(organizationID, user.ID, itemID)
+
+	if errors.Is(err, services.ErrItemHasVariants) || errors.Is(err, services.ErrStockReserved) || errors.Is(err, services.ErrItemInTransit) {
+		return c.Status(http.StatusConflict).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err == nil {
+		return c.JSON(models.Response[any]{
+			Success: true,
+			Message: "item deleted",
+		})
+	}
+
+	return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+		Success: false,
+		Message: "item failed to delete",
+	})
+}
+
+func GetItemByBarcode(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	item, err := services.GetItemByBarcode(organizationID, c.Params("code"))
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.Item]{
+		Success: true,
+		Message: "item found",
+		Data:    item,
+	})
+}
+
+func SearchItems(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var query string = c.Query("q")
+
+	if query == "" {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: "q is required",
+		})
+	}
+
+	var limit int = c.QueryInt("limit", 20)
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	var results []models.ItemSearchResult = services.SearchItems(organizationID, query, limit)
+
+	return c.JSON(models.Response[[]models.ItemSearchResult]{
+		Success: true,
+		Message: "search results",
+		Data:    results,
+	})
+}
+
+func SuggestItems(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var limit int = c.QueryInt("limit", 10)
+
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	var suggestions []models.ItemSuggestion = services.SuggestItems(organizationID, c.Query("q"), limit)
+
+	return c.JSON(models.Response[[]models.ItemSuggestion]{
+		Success: true,
+		Message: "suggestions",
+		Data:    suggestions,
+	})
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CreateInvitation(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var invitationInput *models.InvitationRequest = new(models.InvitationRequest)
+
+	if err := c.BodyParser(invitationInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := invitationInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	invitation, err := services.CreateInvitation(organizationID, user, *invitationInput)
+
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.Invitation]{
+		Success: true,
+		Message: "invitation sent",
+		Data:    invitation,
+	})
+}
+
+func GetInvitations(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var invitations []models.Invitation = services.GetInvitations(organizationID)
+
+	return c.JSON(models.Response[[]models.Invitation]{
+		Success: true,
+		Message: "All invitations data",
+		Data:    invitations,
+	})
+}
+
+func RevokeInvitation(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var invitationID string = c.Params("id")
+
+	if err := services.RevokeInvitation(organizationID, invitationID); err != nil {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "invitation revoked",
+	})
+}
+
+func AcceptInvitation(c *fiber.Ctx) error {
+	var acceptInput *models.AcceptInvitationRequest = new(models.AcceptInvitationRequest)
+
+	if err := c.BodyParser(acceptInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := acceptInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	token, err := services.AcceptInvitation(*acceptInput)
+
+	if errors.Is(err, services.ErrAccountDisabled) {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	//the existing user must log in to use the organization
+	if token == nil {
+		return c.JSON(models.Response[any]{
+			Success: true,
+			Message: "invitation accepted, log in to continue",
+		})
+	}
+
+	return c.JSON(models.Response[models.TokenPair]{
+		Success: true,
+		Message: "token data",
+		Data:    *token,
+	})
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetItemLabel(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var format string = c.Query("format", models.LabelFormatPNG)
+
+	if format != models.LabelFormatPNG && format != models.LabelFormatPDF {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: "format must be one of png pdf",
+		})
+	}
+
+	label, err := services.GetItemLabel(organizationID, c.Params("id"), format)
+
+	if err != nil {
+		return sendLabelError(c, err)
+	}
+
+	if format == models.LabelFormatPDF {
+		c.Set(fiber.HeaderContentType, "application/pdf")
+	} else {
+		c.Set(fiber.HeaderContentType, "image/png")
+	}
+
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="label.`+format+`"`)
+
+	return c.Send(label)
+}
+
+func CreateItemLabelSheet(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var labelSheetInput *models.LabelSheetRequest = new(models.LabelSheetRequest)
+
+	if err := c.BodyParser(labelSheetInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := labelSheetInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	sheet, err := services.GetItemLabelSheet(organizationID, labelSheetInput.ItemIDs)
+
+	if err != nil {
+		return sendLabelError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="labels.pdf"`)
+
+	return c.Send(sheet)
+}
+
+func sendLabelError(c *fiber.Ctx, err error) error {
+	var status int = http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, services.ErrItemNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, utils.ErrLabelCodeTooLong):
+		status = http.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(models.Response[any]{
+		Success: false,
+		Message: err.Error(),
+	})
+}
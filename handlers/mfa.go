@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func EnrollTOTP(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if _, ok := utils.GetCurrentAPIKey(c); ok {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: "mfa cannot be managed with an api key",
+		})
+	}
+
+	enrollment, err := services.EnrollTOTP(user)
+
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.TOTPEnrollment]{
+		Success: true,
+		Message: "confirm the enrollment with a code from the authenticator app",
+		Data:    enrollment,
+	})
+}
+
+func ConfirmTOTP(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if _, ok := utils.GetCurrentAPIKey(c); ok {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: "mfa cannot be managed with an api key",
+		})
+	}
+
+	var codeInput *models.TOTPCodeRequest = new(models.TOTPCodeRequest)
+
+	if err := c.BodyParser(codeInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := codeInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	recoveryCodes, err := services.ConfirmTOTP(user, codeInput.Code)
+
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.RecoveryCodes]{
+		Success: true,
+		Message: "totp enabled, the recovery codes are only shown once",
+		Data:    recoveryCodes,
+	})
+}
+
+func DisableTOTP(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if _, ok := utils.GetCurrentAPIKey(c); ok {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: "mfa cannot be managed with an api key",
+		})
+	}
+
+	var codeInput *models.TOTPCodeRequest = new(models.TOTPCodeRequest)
+
+	if err := c.BodyParser(codeInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := codeInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	if err := services.DisableTOTP(user, codeInput.Code); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "totp disabled",
+	})
+}
+
+func LoginWithMFA(c *fiber.Ctx) error {
+	var mfaInput *models.MFALoginRequest = new(models.MFALoginRequest)
+
+	if err := c.BodyParser(mfaInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := mfaInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	token, err := services.LoginWithMFA(*mfaInput, c.IP())
+
+	var blockedError services.LoginBlockedError
+
+	if errors.As(err, &blockedError) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(blockedError.RetryAfter.Seconds()))))
+		return c.Status(http.StatusTooManyRequests).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, services.ErrAccountDisabled) {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.TokenPair]{
+		Success: true,
+		Message: "token data",
+		Data:    token,
+	})
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetItemMovements(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var movementQuery *models.MovementQuery = new(models.MovementQuery)
+
+	if err := c.QueryParser(movementQuery); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := movementQuery.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	movements, err := services.GetItemMovements(organizationID, c.Params("id"), *movementQuery)
+
+	if err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.JSON(models.Response[[]models.StockMovement]{
+		Success: true,
+		Message: "All movements data",
+		Data:    movements,
+	})
+}
+
+func CreateItemMovement(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var movementInput *models.StockMovementRequest = new(models.StockMovementRequest)
+
+	if err := c.BodyParser(movementInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := movementInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	movements, err := services.CreateStockMovement(organizationID, user.ID, c.Params("id"), *movementInput)
+
+	if err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[[]models.StockMovement]{
+		Success: true,
+		Message: "movement recorded",
+		Data:    movements,
+	})
+}
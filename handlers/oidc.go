@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func StartOIDCLogin(c *fiber.Ctx) error {
+	authURL, err := services.StartOIDCLogin()
+
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Redirect(authURL, http.StatusFound)
+}
+
+func CompleteOIDCLogin(c *fiber.Ctx) error {
+	if providerError := c.Query("error"); providerError != "" {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: providerError,
+		})
+	}
+
+	var state string = c.Query("state")
+	var code string = c.Query("code")
+
+	if state == "" || code == "" {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: "state and code are required",
+		})
+	}
+
+	token, err := services.CompleteOIDCLogin(state, code)
+
+	if errors.Is(err, services.ErrAccountDisabled) || errors.Is(err, services.ErrOIDCUserNotFound) {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.TokenPair]{
+		Success: true,
+		Message: "token data",
+		Data:    token,
+	})
+}
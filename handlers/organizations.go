@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetOrganizations(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var organizations []models.Organization = services.GetOrganizations(user.ID)
+
+	return c.JSON(models.Response[[]models.Organization]{
+		Success: true,
+		Message: "All organizations data",
+		Data:    organizations,
+	})
+}
+
+func CreateOrganization(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var organizationInput *models.OrganizationRequest = new(models.OrganizationRequest)
+
+	if err := c.BodyParser(organizationInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := organizationInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	organization, err := services.CreateOrganization(user, *organizationInput)
+
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.Organization]{
+		Success: true,
+		Message: "organization created",
+		Data:    organization,
+	})
+}
+
+func SwitchOrganization(c *fiber.Ctx) error {
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if _, ok := utils.GetCurrentAPIKey(c); ok {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: "organization cannot be switched with an api key",
+		})
+	}
+
+	var organizationID string = c.Params("id")
+
+	token, err := services.SwitchOrganization(user, organizationID)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.TokenPair]{
+		Success: true,
+		Message: "token data",
+		Data:    token,
+	})
+}
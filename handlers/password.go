@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ForgotPassword(c *fiber.Ctx) error {
+	var forgotInput *models.ForgotPasswordRequest = new(models.ForgotPasswordRequest)
+
+	if err := c.BodyParser(forgotInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	errors := forgotInput.ValidateStruct()
+
+	if errors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    errors,
+		})
+	}
+
+	if err := services.ForgotPassword(*forgotInput); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "if the email is registered, a password reset link has been sent",
+	})
+}
+
+func ResetPassword(c *fiber.Ctx) error {
+	var resetInput *models.ResetPasswordRequest = new(models.ResetPasswordRequest)
+
+	if err := c.BodyParser(resetInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := resetInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	err := services.ResetPassword(*resetInput)
+
+	if errors.Is(err, services.ErrInvalidUserToken) {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "password has been reset",
+	})
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetReservations(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var reservationQuery *models.ReservationQuery = new(models.ReservationQuery)
+
+	if err := c.QueryParser(reservationQuery); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := reservationQuery.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	var reservations []models.Reservation = services.GetReservations(organizationID, *reservationQuery)
+
+	return c.JSON(models.Response[[]models.Reservation]{
+		Success: true,
+		Message: "All reservations data",
+		Data:    reservations,
+	})
+}
+
+func CreateReservation(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var reservationInput *models.ReservationRequest = new(models.ReservationRequest)
+
+	if err := c.BodyParser(reservationInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := reservationInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	reservation, err := services.CreateReservation(organizationID, user.ID, *reservationInput)
+
+	if err != nil {
+		return sendReservationError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.Reservation]{
+		Success: true,
+		Message: "reservation created",
+		Data:    reservation,
+	})
+}
+
+func ReleaseReservation(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	reservation, err := services.ReleaseReservation(organizationID, c.Params("id"))
+
+	if err != nil {
+		return sendReservationError(c, err)
+	}
+
+	return c.JSON(models.Response[models.Reservation]{
+		Success: true,
+		Message: "reservation released",
+		Data:    reservation,
+	})
+}
+
+func FulfillReservation(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var fulfillInput *models.FulfillReservationRequest = new(models.FulfillReservationRequest)
+
+	if err := c.BodyParser(fulfillInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := fulfillInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	reservation, err := services.FulfillReservation(organizationID, user.ID, c.Params("id"), *fulfillInput)
+
+	if err != nil {
+		return sendReservationError(c, err)
+	}
+
+	return c.JSON(models.Response[models.Reservation]{
+		Success: true,
+		Message: "reservation fulfilled",
+		Data:    reservation,
+	})
+}
+
+func sendReservationError(c *fiber.Ctx, err error) error {
+	var status int = http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, services.ErrReservationNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrItemNotFound), errors.Is(err, services.ErrLocationNotFound):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrReservationNotActive), errors.Is(err, services.ErrInsufficientAvailability), errors.Is(err, services.ErrInsufficientStock):
+		status = http.StatusConflict
+	}
+
+	return c.Status(status).JSON(models.Response[any]{
+		Success: false,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetTransferOrders(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var transferQuery *models.TransferQuery = new(models.TransferQuery)
+
+	if err := c.QueryParser(transferQuery); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := transferQuery.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	var transfers []models.TransferOrder = services.GetTransferOrders(organizationID, *transferQuery)
+
+	return c.JSON(models.Response[[]models.TransferOrder]{
+		Success: true,
+		Message: "All transfer orders data",
+		Data:    transfers,
+	})
+}
+
+func GetTransferOrderByID(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	transfer, err := services.GetTransferOrderByID(organizationID, c.Params("id"))
+
+	if err != nil {
+		return sendTransferError(c, err)
+	}
+
+	return c.JSON(models.Response[models.TransferOrder]{
+		Success: true,
+		Message: "transfer order found",
+		Data:    transfer,
+	})
+}
+
+func CreateTransferOrder(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var transferInput *models.TransferOrderRequest = new(models.TransferOrderRequest)
+
+	if err := c.BodyParser(transferInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := transferInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	transfer, err := services.CreateTransferOrder(organizationID, user.ID, *transferInput)
+
+	if err != nil {
+		return sendTransferError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.TransferOrder]{
+		Success: true,
+		Message: "transfer order created",
+		Data:    transfer,
+	})
+}
+
+func ShipTransferOrder(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	transfer, err := services.ShipTransferOrder(organizationID, user.ID, c.Params("id"))
+
+	if err != nil {
+		return sendTransferError(c, err)
+	}
+
+	return c.JSON(models.Response[models.TransferOrder]{
+		Success: true,
+		Message: "transfer order shipped",
+		Data:    transfer,
+	})
+}
+
+func ReceiveTransferOrder(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var receiptInput *models.TransferReceiptRequest = new(models.TransferReceiptRequest)
+
+	if err := c.BodyParser(receiptInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := receiptInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	transfer, err := services.ReceiveTransferOrder(organizationID, user.ID, c.Params("id"), *receiptInput)
+
+	if err != nil {
+		return sendTransferError(c, err)
+	}
+
+	return c.JSON(models.Response[models.TransferOrder]{
+		Success: true,
+		Message: "transfer order received",
+		Data:    transfer,
+	})
+}
+
+func CancelTransferOrder(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	transfer, err := services.CancelTransferOrder(organizationID, user.ID, c.Params("id"))
+
+	if err != nil {
+		return sendTransferError(c, err)
+	}
+
+	return c.JSON(models.Response[models.TransferOrder]{
+		Success: true,
+		Message: "transfer order cancelled",
+		Data:    transfer,
+	})
+}
+
+func sendTransferError(c *fiber.Ctx, err error) error {
+	var status int = http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, services.ErrTransferNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrItemNotFound), errors.Is(err, services.ErrLocationNotFound),
+		errors.Is(err, services.ErrTransferLineNotFound), errors.Is(err, services.ErrTransferOverReceipt):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrTransferStatus), errors.Is(err, services.ErrInsufficientStock), errors.Is(err, services.ErrStockReserved):
+		status = http.StatusConflict
+	}
+
+	return c.Status(status).JSON(models.Response[any]{
+		Success: false,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func UnlockUser(c *fiber.Ctx) error {
+	currentUser, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var userID string = c.Params("id")
+
+	user, err := services.GetUserByID(userID)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := services.UnlockUser(user, currentUser); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "user unlocked",
+	})
+}
+
+func GetLoginEvents(c *fiber.Ctx) error {
+	var limit int = c.QueryInt("limit", 100)
+
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	var events []models.LoginEvent = services.GetLoginEvents(c.Query("type"), c.Query("email"), c.Query("ip_address"), limit)
+
+	return c.JSON(models.Response[[]models.LoginEvent]{
+		Success: true,
+		Message: "All login events data",
+		Data:    events,
+	})
+}
+
+func GetUsers(c *fiber.Ctx) error {
+	var userQuery *models.UserQuery = new(models.UserQuery)
+
+	if err := c.QueryParser(userQuery); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := userQuery.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	if userQuery.Page == 0 {
+		userQuery.Page = 1
+	}
+
+	if userQuery.PerPage == 0 {
+		userQuery.PerPage = 20
+	}
+
+	users, total := services.GetUsers(*userQuery)
+
+	return c.JSON(models.PaginatedResponse[[]models.User]{
+		Response: models.Response[[]models.User]{
+			Success: true,
+			Message: "All users data",
+			Data:    users,
+		},
+		Pagination: models.Pagination{
+			Page:    userQuery.Page,
+			PerPage: userQuery.PerPage,
+			Total:   total,
+		},
+	})
+}
+
+func GetUserByID(c *fiber.Ctx) error {
+	var userID string = c.Params("id")
+
+	user, err := services.GetUserByID(userID)
+
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.User]{
+		Success: true,
+		Message: "user found",
+		Data:    user,
+	})
+}
+
+func UpdateUser(c *fiber.Ctx) error {
+	currentUser, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var userInput *models.UpdateUserRequest = new(models.UpdateUserRequest)
+
+	if err := c.BodyParser(userInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := userInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	var userID string = c.Params("id")
+
+	user, err := services.UpdateUser(userID, *userInput, currentUser)
+
+	if errors.Is(err, services.ErrUserNotFound) {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[models.User]{
+		Success: true,
+		Message: "user updated",
+		Data:    user,
+	})
+}
+
+func DeleteUser(c *fiber.Ctx) error {
+	currentUser, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var userID string = c.Params("id")
+
+	err = services.DeleteUser(userID, currentUser)
+
+	if errors.Is(err, services.ErrUserNotFound) {
+		return c.Status(http.StatusNotFound).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "user deleted",
+	})
+}
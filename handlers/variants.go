@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GenerateVariants(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var variantInput *models.VariantMatrixRequest = new(models.VariantMatrixRequest)
+
+	if err := c.BodyParser(variantInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := variantInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	item, err := services.GenerateVariants(organizationID, c.Params("id"), *variantInput)
+
+	if err != nil {
+		var status int = http.StatusInternalServerError
+
+		switch {
+		case errors.Is(err, services.ErrItemNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrVariantOfVariant), errors.Is(err, services.ErrVariantAxesMismatch),
+			errors.Is(err, services.ErrTooManyVariants), errors.Is(err, services.ErrVariantSKUTooLong):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrDuplicateSKU):
+			status = http.StatusConflict
+		}
+
+		return c.Status(status).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.Item]{
+		Success: true,
+		Message: "variants generated",
+		Data:    item,
+	})
+}
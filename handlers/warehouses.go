@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetWarehouses(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var warehouses []models.Warehouse = services.GetWarehouses(organizationID)
+
+	return c.JSON(models.Response[[]models.Warehouse]{
+		Success: true,
+		Message: "All warehouses data",
+		Data:    warehouses,
+	})
+}
+
+func CreateWarehouse(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var warehouseInput *models.WarehouseRequest = new(models.WarehouseRequest)
+
+	if err := c.BodyParser(warehouseInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := warehouseInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	warehouse, err := services.CreateWarehouse(organizationID, *warehouseInput)
+
+	if err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.Warehouse]{
+		Success: true,
+		Message: "warehouse created",
+		Data:    warehouse,
+	})
+}
+
+func UpdateWarehouse(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var warehouseInput *models.WarehouseRequest = new(models.WarehouseRequest)
+
+	if err := c.BodyParser(warehouseInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := warehouseInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	warehouse, err := services.UpdateWarehouse(organizationID, c.Params("id"), *warehouseInput)
+
+	if err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.JSON(models.Response[models.Warehouse]{
+		Success: true,
+		Message: "warehouse updated",
+		Data:    warehouse,
+	})
+}
+
+func DeleteWarehouse(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := services.DeleteWarehouse(organizationID, c.Params("id")); err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "warehouse deleted",
+	})
+}
+
+func GetLocations(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	locations, err := services.GetLocations(organizationID, c.Params("id"))
+
+	if err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.JSON(models.Response[[]models.Location]{
+		Success: true,
+		Message: "All locations data",
+		Data:    locations,
+	})
+}
+
+func CreateLocation(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var locationInput *models.LocationRequest = new(models.LocationRequest)
+
+	if err := c.BodyParser(locationInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := locationInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	location, err := services.CreateLocation(organizationID, c.Params("id"), *locationInput)
+
+	if err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(models.Response[models.Location]{
+		Success: true,
+		Message: "location created",
+		Data:    location,
+	})
+}
+
+func UpdateLocation(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var locationInput *models.LocationRequest = new(models.LocationRequest)
+
+	if err := c.BodyParser(locationInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := locationInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	location, err := services.UpdateLocation(organizationID, c.Params("id"), c.Params("locationId"), *locationInput)
+
+	if err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.JSON(models.Response[models.Location]{
+		Success: true,
+		Message: "location updated",
+		Data:    location,
+	})
+}
+
+func DeleteLocation(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := services.DeleteLocation(organizationID, c.Params("id"), c.Params("locationId")); err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.JSON(models.Response[any]{
+		Success: true,
+		Message: "location deleted",
+	})
+}
+
+func GetItemStock(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	stock, err := services.GetItemStock(organizationID, c.Params("id"))
+
+	if err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.JSON(models.Response[models.ItemStock]{
+		Success: true,
+		Message: "item stock",
+		Data:    stock,
+	})
+}
+
+func SetItemStock(c *fiber.Ctx) error {
+	organizationID, err := utils.GetCurrentOrganizationID(c)
+
+	if err != nil {
+		return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	var stockInput *models.StockLevelRequest = new(models.StockLevelRequest)
+
+	if err := c.BodyParser(stockInput); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	validationErrors := stockInput.ValidateStruct()
+
+	if validationErrors != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Response[[]*models.ErrorResponse]{
+			Success: false,
+			Message: "validation failed",
+			Data:    validationErrors,
+		})
+	}
+
+	user, err := utils.GetCurrentUser(c)
+
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	stock, err := services.SetItemStock(organizationID, user.ID, c.Params("id"), c.Params("locationId"), stockInput.Quantity)
+
+	if err != nil {
+		return sendWarehouseError(c, err)
+	}
+
+	return c.JSON(models.Response[models.ItemStock]{
+		Success: true,
+		Message: "item stock updated",
+		Data:    stock,
+	})
+}
+
+func sendWarehouseError(c *fiber.Ctx, err error) error {
+	var status int = http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, services.ErrWarehouseNotFound), errors.Is(err, services.ErrLocationNotFound), errors.Is(err, services.ErrItemNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrWarehouseNotEmpty), errors.Is(err, services.ErrLocationNotEmpty),
+		errors.Is(err, services.ErrDuplicateWarehouse), errors.Is(err, services.ErrDuplicateLocation), errors.Is(err, services.ErrInsufficientStock),
+		errors.Is(err, services.ErrStockReserved):
+		status = http.StatusConflict
+	}
+
+	return c.Status(status).JSON(models.Response[any]{
+		Success: false,
+		Message: err.Error(),
+	})
+}
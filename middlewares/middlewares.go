@@ -1,36 +1,181 @@
-package middlewares
-
-import (
-	"inventory-project-testing/utils"
-	"github.com/gofiber/fiber/v2"
-	jwtMiddleware "github.com/gofiber/jwt/v3"
-)
-
-//CreateMiddleware return a middleware with JWT authentication
-func CreateMiddleware() func(*fiber.Ctx) error {
-	//create a JWT middleware
-	config := jwtMiddleware.Config{
-		SigningKey: []byte(utils.GetValue("JWT_SECRET_KEY")),
-		ContextKey : "jwt",
-		ErrorHandler : jwtError,
-	}
-
-	//return the JWT middleware
-	return jwtMiddleware.New(config)
-}
-
-func jwtError (c *fiber.Ctx, err error) error{
-	//if the error is caused by malformed JWT token
-	//return an error
-	if err.Error() == "Missing or malformed JWT" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"message": err.Error(),
-		})
-	}
-
-	//if the error is caused by other error 
-	//return an error
-	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-		"message": err.Error(),
-	})
-}
\ No newline at end of file
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/services"
+	"inventory-project-testing/utils"
+	"github.com/gofiber/fiber/v2"
+	jwtMiddleware "github.com/gofiber/jwt/v3"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+//CreateMiddleware return a middleware with JWT authentication
+//machine clients can use the X-API-Key header instead of the JWT token
+func CreateMiddleware() func(*fiber.Ctx) error {
+	//create a JWT middleware
+	config := jwtMiddleware.Config{
+		KeyFunc: services.JWTKeyFunc,
+		ContextKey : "jwt",
+		SuccessHandler : jwtSuccess,
+		ErrorHandler : jwtError,
+	}
+
+	var jwtHandler fiber.Handler = jwtMiddleware.New(config)
+
+	//return the authentication middleware
+	return func(c *fiber.Ctx) error {
+		//if the API key is provided, authenticate with the API key
+		if key := c.Get("X-API-Key"); key != "" {
+			return apiKeyAuthentication(c, key)
+		}
+
+		return jwtHandler(c)
+	}
+}
+
+func apiKeyAuthentication (c *fiber.Ctx, key string) error{
+	//find the API key and its owner
+	apiKey, user, err := services.AuthenticateAPIKey(key)
+	if err != nil {
+		return jwtError(c, err)
+	}
+
+	//store the user and the API key for the handlers
+	utils.SetCurrentUser(c, user)
+	utils.SetCurrentAPIKey(c, apiKey)
+
+	//store the membership of the organization of the API key
+	if err := setCurrentMembership(c, apiKey.OrganizationID, user.ID); err != nil {
+		return jwtError(c, err)
+	}
+
+	return c.Next()
+}
+
+func jwtSuccess (c *fiber.Ctx) error{
+	//get the verified token from the context
+	token := c.Locals("jwt").(*jwt.Token)
+
+	//get the identity claims from the token
+	claims, err := utils.GetTokenMetadata(token)
+	if err != nil {
+		return jwtError(c, err)
+	}
+
+	//if the token is in the denylist, return an error
+	if services.IsTokenRevoked(claims.TokenID) {
+		return jwtError(c, errors.New("token has been revoked"))
+	}
+
+	//find the user who owns the token
+	user, err := services.GetUserByID(claims.UserID)
+	if err != nil {
+		return jwtError(c, err)
+	}
+
+	//if the account is disabled, return an error
+	if user.DisabledAt != nil {
+		return jwtError(c, services.ErrAccountDisabled)
+	}
+
+	//store the user and the token for the handlers
+	utils.SetCurrentUser(c, user)
+	utils.SetCurrentTokenMetadata(c, claims)
+
+	//store the membership of the organization in the token
+	if err := setCurrentMembership(c, claims.OrganizationID, user.ID); err != nil {
+		return jwtError(c, err)
+	}
+
+	return c.Next()
+}
+
+//setCurrentMembership stores the membership of the selected organization
+//if the user is removed from the organization, an error is returned
+func setCurrentMembership(c *fiber.Ctx, organizationID string, userID string) error {
+	//the user has not selected any organization
+	if organizationID == "" {
+		return nil
+	}
+
+	membership, err := services.GetMembership(organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	utils.SetCurrentMembership(c, membership)
+	return nil
+}
+
+func jwtError (c *fiber.Ctx, err error) error{
+	//if the error is caused by malformed JWT token
+	//return an error
+	if err.Error() == "Missing or malformed JWT" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	//if the error is caused by other error 
+	//return an error
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"message": err.Error(),
+	})
+}
+
+//RequirePermission return a middleware that checks the permission of the current user
+func RequirePermission(permission string) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		//make sure the user is authenticated
+		if _, err := utils.GetCurrentUser(c); err != nil {
+			return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		//if the user is not granted the permission
+		//return an error
+		if !utils.HasPermission(c, permission) {
+			return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+				Success: false,
+				Message: "permission denied",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+//RequireVerifiedEmail return a middleware that blocks users with unverified email
+//the policy is configured with REQUIRE_EMAIL_VERIFICATION in the .env file
+func RequireVerifiedEmail() func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		//if the policy is disabled, continue the request
+		if !services.IsEmailVerificationRequired() {
+			return c.Next()
+		}
+
+		//get the authenticated user
+		user, err := utils.GetCurrentUser(c)
+		if err != nil {
+			return c.Status(http.StatusUnauthorized).JSON(models.Response[any]{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		//if the email is not verified, return an error
+		if user.EmailVerifiedAt == nil {
+			return c.Status(http.StatusForbidden).JSON(models.Response[any]{
+				Success: false,
+				Message: "email is not verified",
+			})
+		}
+
+		return c.Next()
+	}
+}
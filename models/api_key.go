@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+//APIKey is a personal key used by machine clients instead of the JWT token
+//only the hash of the key is stored, the key itself is shown once
+type APIKey struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id" gorm:"index"`
+	// the organization that the key can access
+	OrganizationID string     `json:"organization_id"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	KeyHash        string     `json:"-" gorm:"unique"`
+	Scopes         string     `json:"scopes"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+//AllowsPermission returns true if the scopes of the key grant the permission
+//a key without scopes is granted every permission of its owner
+func (apiKey APIKey) AllowsPermission(permission string) bool {
+	if apiKey.Scopes == "" {
+		return true
+	}
+
+	for _, scope := range strings.Fields(apiKey.Scopes) {
+		if scope == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+//CreatedAPIKey is returned once when the API key is created
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+//APIKeyRequest is used to create a new API key
+type APIKeyRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes" validate:"dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (apiKeyInput APIKeyRequest) ValidateStruct() []*ErrorResponse {
+	var errors []*ErrorResponse = validateStruct(apiKeyInput)
+
+	//every scope must be a known permission
+	for _, scope := range apiKeyInput.Scopes {
+		if scope != "" && !IsValidPermission(scope) {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: "the scope " + scope + " is invalid",
+				Field:        "Scopes",
+			})
+		}
+	}
+
+	//the expiration time must be in the future
+	if apiKeyInput.ExpiresAt != nil && !apiKeyInput.ExpiresAt.After(time.Now()) {
+		errors = append(errors, &ErrorResponse{
+			ErrorMessage: "the expiration time must be in the future",
+			Field:        "ExpiresAt",
+		})
+	}
+
+	return errors
+}
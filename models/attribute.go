@@ -0,0 +1,213 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//the types of the custom attributes
+const (
+	AttributeTypeString = "string"
+	AttributeTypeNumber = "number"
+	AttributeTypeBool   = "bool"
+	AttributeTypeEnum   = "enum"
+	AttributeTypeDate   = "date"
+)
+
+//the operators of the attribute filters
+const (
+	AttributeFilterEqual            = "eq"
+	AttributeFilterGreaterThanEqual = "gte"
+	AttributeFilterLessThanEqual    = "lte"
+)
+
+//attributeNamePattern is the format of the attribute name
+//the name is used as the key of the attribute and in the filters of the item list
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+//AttributeDefinition is a custom attribute of the items in the category
+//the items of the subcategories have the attributes of every ancestor category
+type AttributeDefinition struct {
+	ID             string   `json:"id"`
+	OrganizationID string   `json:"organization_id" gorm:"size:191;index"`
+	CategoryID     string   `json:"category_id" gorm:"size:191;index"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Required       bool     `json:"required"`
+	// the allowed values of the enum attribute
+	Options []string `json:"options" gorm:"serializer:json"`
+	// the range of the number or the length of the string
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+	// the regular expression that the string must match
+	Pattern   string    `json:"pattern"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//AttributeDefinitionRequest is used to create or update the attribute of the category
+type AttributeDefinitionRequest struct {
+	Name     string   `json:"name" validate:"required"`
+	Type     string   `json:"type" validate:"required,oneof=string number bool enum date"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options" validate:"required_if=Type enum,max=100,unique,dive,required,max=100"`
+	Min      *float64 `json:"min"`
+	Max      *float64 `json:"max"`
+	Pattern  string   `json:"pattern" validate:"max=200"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (attributeInput AttributeDefinitionRequest) ValidateStruct() []*ErrorResponse {
+	var errors []*ErrorResponse = validateStruct(attributeInput)
+
+	//the name must be a lowercase key
+	if attributeInput.Name != "" && !attributeNamePattern.MatchString(attributeInput.Name) {
+		errors = append(errors, &ErrorResponse{
+			ErrorMessage: "the name must start with a letter and contain lowercase letters, numbers and underscores only",
+			Field:        "Name",
+		})
+	}
+
+	//the options are only used by the enum attribute
+	if attributeInput.Type != AttributeTypeEnum && len(attributeInput.Options) > 0 {
+		errors = append(errors, &ErrorResponse{
+			ErrorMessage: "the options can only be used by the enum attribute",
+			Field:        "Options",
+		})
+	}
+
+	//the pattern is only used by the string attribute
+	if attributeInput.Pattern != "" {
+		if attributeInput.Type != AttributeTypeString {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: "the pattern can only be used by the string attribute",
+				Field:        "Pattern",
+			})
+		} else if _, err := regexp.Compile(attributeInput.Pattern); err != nil {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: "the pattern is not a valid regular expression",
+				Field:        "Pattern",
+			})
+		}
+	}
+
+	//the range is only used by the number and string attributes
+	if attributeInput.Min != nil || attributeInput.Max != nil {
+		if attributeInput.Type != AttributeTypeNumber && attributeInput.Type != AttributeTypeString {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: "the range can only be used by the number and string attributes",
+				Field:        "Min",
+			})
+		} else if attributeInput.Min != nil && attributeInput.Max != nil && *attributeInput.Min > *attributeInput.Max {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: "the value of Min must be less than or equals Max",
+				Field:        "Min",
+			})
+		}
+	}
+
+	return errors
+}
+
+//ValidateValue returns the error message if the value does not match the attribute
+//the value is decoded from JSON, the numbers are float64
+func (definition AttributeDefinition) ValidateValue(value any) (string, bool) {
+	switch definition.Type {
+	case AttributeTypeString:
+		text, ok := value.(string)
+		if !ok {
+			return "the value of " + definition.Name + " must be a string", false
+		}
+
+		var length float64 = float64(len([]rune(text)))
+		if definition.Min != nil && length < *definition.Min {
+			return fmt.Sprintf("the minimum length of %s is equals %v", definition.Name, *definition.Min), false
+		}
+
+		if definition.Max != nil && length > *definition.Max {
+			return fmt.Sprintf("the maximum length of %s is equals %v", definition.Name, *definition.Max), false
+		}
+
+		if definition.Pattern != "" {
+			if pattern, err := regexp.Compile(definition.Pattern); err == nil && !pattern.MatchString(text) {
+				return "the value of " + definition.Name + " does not match the pattern " + definition.Pattern, false
+			}
+		}
+	case AttributeTypeNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return "the value of " + definition.Name + " must be a number", false
+		}
+
+		if definition.Min != nil && number < *definition.Min {
+			return fmt.Sprintf("the value of %s must be greater than or equals %v", definition.Name, *definition.Min), false
+		}
+
+		if definition.Max != nil && number > *definition.Max {
+			return fmt.Sprintf("the value of %s must be less than or equals %v", definition.Name, *definition.Max), false
+		}
+	case AttributeTypeBool:
+		if _, ok := value.(bool); !ok {
+			return "the value of " + definition.Name + " must be true or false", false
+		}
+	case AttributeTypeEnum:
+		text, _ := value.(string)
+		for _, option := range definition.Options {
+			if option == text {
+				return "", true
+			}
+		}
+
+		return "the value of " + definition.Name + " must be one of " + strings.Join(definition.Options, " "), false
+	case AttributeTypeDate:
+		text, _ := value.(string)
+		if _, err := time.Parse("2006-01-02", text); err != nil {
+			return "the value of " + definition.Name + " must be a valid date", false
+		}
+	}
+
+	return "", true
+}
+
+//AttributeFilter filters the items by the value of the attribute
+type AttributeFilter struct {
+	Name     string
+	Operator string
+	Value    string
+}
+
+//ParseAttributeFilters returns the attribute filters from the query parameters
+//the filters are written as attr.<name>=<value>, attr.<name>.gte=<value> or attr.<name>.lte=<value>
+func ParseAttributeFilters(queries map[string]string) []AttributeFilter {
+	var filters []AttributeFilter
+
+	for key, value := range queries {
+		if !strings.HasPrefix(key, "attr.") {
+			continue
+		}
+
+		var filter AttributeFilter = AttributeFilter{
+			Name:     strings.TrimPrefix(key, "attr."),
+			Operator: AttributeFilterEqual,
+			Value:    value,
+		}
+
+		if name, operator, found := strings.Cut(filter.Name, "."); found {
+			filter.Name = name
+			filter.Operator = operator
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters
+}
+
+//IsNumber returns true if the value of the filter is a number
+func (filter AttributeFilter) IsNumber() bool {
+	_, err := strconv.ParseFloat(filter.Value, 64)
+	return err == nil
+}
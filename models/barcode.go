@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+//the symbologies of the barcodes
+const (
+	SymbologyEAN13   = "ean13"
+	SymbologyUPCA    = "upca"
+	SymbologyEAN8    = "ean8"
+	SymbologyCode128 = "code128"
+)
+
+//ItemBarcode is a barcode printed on the item
+//the barcode is unique inside the organization
+type ItemBarcode struct {
+	ID             string    `json:"-"`
+	OrganizationID string    `json:"-" gorm:"size:191;uniqueIndex:idx_item_barcodes_organization_code"`
+	ItemID         string    `json:"-" gorm:"index"`
+	Code           string    `json:"code" gorm:"size:80;uniqueIndex:idx_item_barcodes_organization_code"`
+	Symbology      string    `json:"symbology"`
+	CreatedAt      time.Time `json:"-"`
+}
+
+//GetBarcodeSymbology returns the symbology of the barcode
+//the numeric codes with 8, 12 or 13 digits must have a valid check digit
+//the other codes are Code 128 barcodes with printable ASCII characters
+func GetBarcodeSymbology(code string) (string, bool) {
+	if code == "" || len(code) > 80 {
+		return "", false
+	}
+
+	if isNumeric(code) {
+		switch len(code) {
+		case 13:
+			return SymbologyEAN13, hasValidCheckDigit(code)
+		case 12:
+			return SymbologyUPCA, hasValidCheckDigit(code)
+		case 8:
+			return SymbologyEAN8, hasValidCheckDigit(code)
+		}
+	}
+
+	//Code 128 can encode every printable ASCII character
+	for _, character := range code {
+		if character < ' ' || character > '~' {
+			return "", false
+		}
+	}
+
+	return SymbologyCode128, true
+}
+
+//NormalizeBarcode returns the code that is stored for the barcode
+//a UPC-A code is stored as the EAN-13 code with a leading zero, so both forms are the same barcode
+func NormalizeBarcode(code string) string {
+	if symbology, isValid := GetBarcodeSymbology(code); isValid && symbology == SymbologyUPCA {
+		return "0" + code
+	}
+
+	return code
+}
+
+//hasValidCheckDigit validates the GS1 check digit of the EAN-13, UPC-A or EAN-8 code
+func hasValidCheckDigit(code string) bool {
+	var sum int
+
+	//the digits are weighted with 3 and 1 from the right, the check digit is excluded
+	for i := len(code) - 2; i >= 0; i-- {
+		var digit int = int(code[i] - '0')
+
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+
+		sum += digit
+	}
+
+	return (10-sum%10)%10 == int(code[len(code)-1]-'0')
+}
+
+//isNumeric returns true if the text only contains digits
+func isNumeric(text string) bool {
+	for _, character := range text {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+
+	return text != ""
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+//Category groups the items of the organization
+//the categories are stored as a tree, the root categories have no parent
+type Category struct {
+	ID             string  `json:"id"`
+	OrganizationID string  `json:"organization_id" gorm:"size:191;index"`
+	ParentID       *string `json:"parent_id" gorm:"size:191;index"`
+	Name           string  `json:"name"`
+	Description    string  `json:"description"`
+	// the children are filled when the category tree is returned
+	Children  []Category `json:"children,omitempty" gorm:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+//CategoryRequest is used to create or update the category
+//the category becomes a root category if the parent is not provided
+type CategoryRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Description string  `json:"description" validate:"max=500"`
+	ParentID    *string `json:"parent_id" validate:"omitempty,uuid"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (categoryInput CategoryRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(categoryInput)
+}
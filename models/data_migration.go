@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+//DataMigration records the one-time migration of the existing data
+//the migration is not run again once it is recorded
+type DataMigration struct {
+	ID        string    `json:"id" gorm:"size:191;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,6 +1,6 @@
-package models
-
-type ErrorResponse struct {
-	ErrorMessage string `json:"error_message"`
-	Field        string `json:"field"`
+package models
+
+type ErrorResponse struct {
+	ErrorMessage string `json:"error_message"`
+	Field        string `json:"field"`
 }
\ No newline at end of file
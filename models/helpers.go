@@ -1,23 +1,70 @@
-package models
-
-import "github.com/go-playground/validator/v10"
-
-//getErrorMessage return validation error message
-func getErrorMessage(err validator.FieldError) string {
-	switch err.Tag() {
-	case "required":
-		return err.Field() + " is required"
-	case "gt":
-		return "the value of " + err.Field() + " must be greater than " + err.Param()
-	case "gte":
-		return "the value of " + err.Field() + " must be greater than or equals " + err.Param()
-    // return the error message if email validation is faield
-	case "email":
-		return "the email is invalid"
-    // return the error message if the field's length is not matched the minimum value
-	case "min":
-		return "the minimum length of " + err.Field() + " is equals " + err.Param()
-	default:
-		return "validation error in " + err.Field()
-	}
-}
+package models
+
+import "github.com/go-playground/validator/v10"
+
+//getErrorMessage return validation error message
+func getErrorMessage(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return err.Field() + " is required"
+	case "required_if":
+		return err.Field() + " is required"
+	case "gt":
+		return "the value of " + err.Field() + " must be greater than " + err.Param()
+	case "gte":
+		return "the value of " + err.Field() + " must be greater than or equals " + err.Param()
+    // return the error message if email validation is faield
+	case "email":
+		return "the email is invalid"
+    // return the error message if the field's length is not matched the minimum value
+	case "min":
+		return "the minimum length of " + err.Field() + " is equals " + err.Param()
+	// return the error message if the value is not one of the allowed values
+	case "oneof":
+		return "the value of " + err.Field() + " must be one of " + err.Param()
+	case "lte":
+		return "the value of " + err.Field() + " must be less than or equals " + err.Param()
+	case "max":
+		return "the maximum length of " + err.Field() + " is equals " + err.Param()
+	case "timezone":
+		return "the timezone is invalid"
+	case "bcp47_language_tag":
+		return "the locale is invalid"
+	case "datetime":
+		return "the value of " + err.Field() + " must be a valid date"
+	case "alphanum":
+		return "the value of " + err.Field() + " must contain letters and numbers only"
+	case "printascii":
+		return "the value of " + err.Field() + " must contain printable ASCII characters only"
+	case "unique":
+		return "the values of " + err.Field() + " must be unique"
+	case "uuid":
+		return "the value of " + err.Field() + " must be a valid ID"
+	case "barcode":
+		return "the barcode in " + err.Field() + " must be a valid EAN-13, UPC-A, EAN-8 or Code 128 barcode"
+	default:
+		return "validation error in " + err.Field()
+	}
+}
+
+//validateStruct performs struct based validation for the given request
+func validateStruct(input any) []*ErrorResponse {
+	//create a variable to store validation errors
+	var errors []*ErrorResponse
+
+	//validate the struct with a new validator
+	err := validator.New().Struct(input)
+
+	//if the validation is failed
+	//insert the error inside "errors" variable
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var element ErrorResponse
+			element.ErrorMessage = getErrorMessage(err)
+			element.Field = err.Field()
+			errors = append(errors, &element)
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+//Identity links an account of the OpenID Connect provider to the user
+type Identity struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id" gorm:"index"`
+	Issuer    string    `json:"issuer" gorm:"size:191;uniqueIndex:idx_identities_issuer_subject"`
+	Subject   string    `json:"subject" gorm:"size:191;uniqueIndex:idx_identities_issuer_subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//OIDCState stores a pending OpenID Connect login until the provider redirects back
+//the state can only be used once
+type OIDCState struct {
+	ID           string    `json:"id"`
+	StateHash    string    `json:"-" gorm:"unique"`
+	Nonce        string    `json:"-"`
+	CodeVerifier string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
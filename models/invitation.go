@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+//Invitation is sent by the admin to add an email into the organization
+//the invitation is accepted with a signed token sent to the email
+type Invitation struct {
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organization_id" gorm:"index"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	InvitedBy      string     `json:"invited_by"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+//InvitationRequest is used to invite an email into the organization
+type InvitationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=admin manager clerk viewer"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (invitationInput InvitationRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(invitationInput)
+}
+
+//AcceptInvitationRequest is used to accept the invitation
+//the password is required if the email does not have an account yet
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"omitempty,min=6"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (acceptInput AcceptInvitationRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(acceptInput)
+}
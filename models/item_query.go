@@ -0,0 +1,125 @@
+package models
+
+import "strings"
+
+//ItemSortFields are the fields that can be used to sort the items
+var ItemSortFields = []string{"name", "price", "quantity", "created_at", "updated_at"}
+
+//ItemSort is a sort field of the item list
+type ItemSort struct {
+	Field      string
+	Descending bool
+}
+
+//ItemQuery is used to list the items
+//the dates are written in the YYYY-MM-DD format and both ends of the ranges are included
+type ItemQuery struct {
+	Limit       int    `query:"limit" validate:"gte=0,lte=100"`
+	Cursor      string `query:"cursor"`
+	Name        string `query:"name"`
+	// the items of the descendant categories are included as well
+	CategoryID  string `query:"category_id"`
+	MinPrice    *int   `query:"min_price" validate:"omitempty,gte=0"`
+	MaxPrice    *int   `query:"max_price" validate:"omitempty,gte=0"`
+	MinQuantity *int   `query:"min_quantity" validate:"omitempty,gte=0"`
+	MaxQuantity *int   `query:"max_quantity" validate:"omitempty,gte=0"`
+	CreatedFrom string `query:"created_from" validate:"omitempty,datetime=2006-01-02"`
+	CreatedTo   string `query:"created_to" validate:"omitempty,datetime=2006-01-02"`
+	UpdatedFrom string `query:"updated_from" validate:"omitempty,datetime=2006-01-02"`
+	UpdatedTo   string `query:"updated_to" validate:"omitempty,datetime=2006-01-02"`
+	// the variants of the product
+	ParentID    string `query:"parent_id"`
+	// the items that are stocked in the warehouse or the location
+	WarehouseID string `query:"warehouse_id"`
+	LocationID  string `query:"location_id"`
+	// the tags are separated by comma, the items must have every tag
+	Tags        string `query:"tags"`
+	// the attribute filters are parsed from the attr.<name> parameters
+	AttributeFilters []AttributeFilter `query:"-"`
+	// the sort fields are separated by comma, the "-" prefix sorts in descending order
+	// for example: sort=-price,name
+	Sort string `query:"sort"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (itemQuery ItemQuery) ValidateStruct() []*ErrorResponse {
+	var errors []*ErrorResponse = validateStruct(itemQuery)
+
+	//make sure every attribute filter is valid
+	for _, filter := range itemQuery.AttributeFilters {
+		if !attributeNamePattern.MatchString(filter.Name) {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: "the attribute name " + filter.Name + " is invalid",
+				Field:        "attr." + filter.Name,
+			})
+		}
+
+		if filter.Operator != AttributeFilterEqual && filter.Operator != AttributeFilterGreaterThanEqual && filter.Operator != AttributeFilterLessThanEqual {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: "the attribute filter operator must be one of eq gte lte",
+				Field:        "attr." + filter.Name,
+			})
+		}
+	}
+
+	//make sure every sort field is allowed
+	for _, sort := range itemQuery.GetSort() {
+		if !isItemSortField(sort.Field) {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: "the items cannot be sorted by " + sort.Field,
+				Field:        "Sort",
+			})
+		}
+	}
+
+	return errors
+}
+
+//GetSort returns the sort fields of the query
+//the newest items are returned first if the sort is not provided
+func (itemQuery ItemQuery) GetSort() []ItemSort {
+	var sorts []ItemSort
+
+	for _, field := range strings.Split(itemQuery.Sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		sorts = append(sorts, ItemSort{
+			Field:      strings.TrimPrefix(field, "-"),
+			Descending: strings.HasPrefix(field, "-"),
+		})
+	}
+
+	if len(sorts) == 0 {
+		sorts = []ItemSort{{Field: "created_at", Descending: true}}
+	}
+
+	return sorts
+}
+
+//GetTags returns the lowercase tags of the query
+func (itemQuery ItemQuery) GetTags() []string {
+	var tags []string
+
+	for _, tag := range strings.Split(itemQuery.Tags, ",") {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+//isItemSortField returns true if the items can be sorted by the field
+func isItemSortField(field string) bool {
+	for _, sortField := range ItemSortFields {
+		if sortField == field {
+			return true
+		}
+	}
+
+	return false
+}
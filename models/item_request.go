@@ -1,33 +1,112 @@
-package models
-
-import "github.com/go-playground/validator/v10"
-
-//request to send a request that is related to the item
-type ItemRequest struct {
-	Name     string `json:"name" validate:"required"`
-	Price    int    `json:"price" validate:"required,gt=0"`
-	Quantity int    `json:"quantity" validate:"gte=0"`
-}
-
-//ValidateStruct performs struct based validation
-func (itemInput ItemRequest) ValidateStruct () []*ErrorResponse{
-	//create a variable to store validation errors
-	var errors []*ErrorResponse
-	//create a new validator
-	validate := validator.New()
-	//validate the struct
-	err:= validate.Struct(itemInput)
-	//if the validation is failed
-	//insert the error inside "errors" variable
-
-	if err != nil{
-		for _, err := range err.(validator.ValidationErrors){
-			var element ErrorResponse
-			element.ErrorMessage = getErrorMessage(err)
-			element.Field = err.Field()
-			errors = append(errors, &element)
-		}
-	}
-
-	return errors
+package models
+
+import (
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+)
+
+//request to send a request that is related to the item
+type ItemRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Description string `json:"description" validate:"max=2000"`
+	// the SKU is generated if it is not provided
+	SKU      string `json:"sku" validate:"omitempty,max=64,printascii"`
+	// every barcode must have a valid check digit
+	Barcodes []string `json:"barcodes" validate:"max=10,unique,dive,barcode"`
+	// the item is not assigned to a category if the category is not provided
+	CategoryID *string `json:"category_id" validate:"omitempty,uuid"`
+	// the tags are stored in lowercase
+	Tags     []string `json:"tags" validate:"max=20,dive,required,max=50"`
+	// the attributes must be defined by the category of the item or its ancestors
+	Attributes map[string]any `json:"attributes"`
+	Price    int    `json:"price" validate:"required,gt=0"`
+	Quantity int    `json:"quantity" validate:"gte=0"`
+}
+
+//ValidateStruct performs struct based validation
+//the attributes are validated against the attribute definitions of the category
+func (itemInput ItemRequest) ValidateStruct (definitions ...AttributeDefinition) []*ErrorResponse{
+	//create a variable to store validation errors
+	var errors []*ErrorResponse
+	//create a new validator
+	validate := validator.New()
+	//register the barcode validation
+	validate.RegisterValidation("barcode", func(field validator.FieldLevel) bool {
+		_, isValid := GetBarcodeSymbology(field.Field().String())
+		return isValid
+	})
+	//validate the struct
+	err:= validate.Struct(itemInput)
+	//if the validation is failed
+	//insert the error inside "errors" variable
+
+	if err != nil{
+		for _, err := range err.(validator.ValidationErrors){
+			var element ErrorResponse
+			element.ErrorMessage = getErrorMessage(err)
+			element.Field = err.Field()
+			errors = append(errors, &element)
+		}
+	}
+
+	//validate the attributes with their definitions
+	errors = append(errors, validateAttributes(itemInput.Attributes, definitions)...)
+
+	return errors
+}
+
+//validateAttributes returns validation errors of the attributes
+//the unknown attributes are rejected and the required attributes must be provided
+func validateAttributes(attributes map[string]any, definitions []AttributeDefinition) []*ErrorResponse {
+	var errors []*ErrorResponse
+	var definitionsByName map[string]AttributeDefinition = map[string]AttributeDefinition{}
+
+	for _, definition := range definitions {
+		definitionsByName[definition.Name] = definition
+
+		//if the required attribute is not provided, return the error
+		if value, ok := attributes[definition.Name]; definition.Required && (!ok || value == nil) {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: definition.Name + " is required",
+				Field:        "Attributes." + definition.Name,
+			})
+		}
+	}
+
+	//the attributes are validated in the order of their names
+	var names []string
+	for name := range attributes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		var value any = attributes[name]
+
+		definition, ok := definitionsByName[name]
+		if !ok {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: name + " is not an attribute of the category",
+				Field:        "Attributes." + name,
+			})
+
+			continue
+		}
+
+		//the optional attribute can be empty
+		if value == nil {
+			continue
+		}
+
+		if message, isValid := definition.ValidateValue(value); !isValid {
+			errors = append(errors, &ErrorResponse{
+				ErrorMessage: message,
+				Field:        "Attributes." + name,
+			})
+		}
+	}
+
+	return errors
 }
\ No newline at end of file
@@ -0,0 +1,15 @@
+package models
+
+//ItemSearchResult is an item matched by the search
+//the matched words are wrapped with the <mark> tag in the highlights
+type ItemSearchResult struct {
+	Item       Item              `json:"item"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+//ItemSuggestion is an item name that completes the prefix
+type ItemSuggestion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
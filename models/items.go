@@ -1,16 +1,45 @@
-package models
-
-import "time"
-
-type Item struct {
-    // the ID field will be filled with uuid data from the faker
-    ID        string    `json:"id" faker:"uuid_hyphenated"`
-    // the Name field will be filled with name data from the faker
-    Name      string    `json:"name" faker:"name"`
-    // the Price field will be filled with one of these values: 15, 27, 61
-    Price     int       `json:"price" faker:"oneof: 15, 27, 61"`
-    // the Quantity field will be filled with one of these values: 15, 27, 61
-    Quantity  int       `json:"quantity" faker:"oneof: 15, 27, 61"`
-    CreatedAt time.Time `json:"created_at"`
-    UpdatedAt time.Time `json:"updated_at"`
+package models
+
+import "time"
+
+type Item struct {
+    // the ID field will be filled with uuid data from the faker
+    ID        string    `json:"id" faker:"uuid_hyphenated"`
+    // the OrganizationID field decides which organization owns the item
+    OrganizationID string `json:"organization_id" gorm:"size:191;index;uniqueIndex:idx_items_organization_sku" faker:"-"`
+    // the SKU field is unique inside the organization
+    SKU       string    `json:"sku" gorm:"size:64;uniqueIndex:idx_items_organization_sku" faker:"uuid_digit"`
+    // the ParentID field is filled if the item is a variant of another item
+    ParentID  *string   `json:"parent_id" gorm:"size:191;index" faker:"-"`
+    // the VariantAxes field is filled if the item is a product with variants
+    VariantAxes []VariantAxis `json:"variant_axes,omitempty" gorm:"type:json;serializer:json" faker:"-"`
+    // the VariantOptions field contains the axis values of the variant
+    VariantOptions map[string]string `json:"variant_options,omitempty" gorm:"type:json;serializer:json" faker:"-"`
+    // the CategoryID field is empty if the item is not assigned to a category
+    CategoryID *string `json:"category_id" gorm:"size:191;index" faker:"-"`
+    // the Name field will be filled with name data from the faker
+    Name      string    `json:"name" faker:"name"`
+    // the Description field will be filled with a sentence from the faker
+    Description string  `json:"description" faker:"sentence"`
+    // the Price field will be filled with one of these values: 15, 27, 61
+    Price     int       `json:"price" faker:"oneof: 15, 27, 61"`
+    // the Quantity field will be filled with one of these values: 15, 27, 61
+    // the quantity is the sum of the stock levels of the item
+    Quantity  int       `json:"quantity" faker:"oneof: 15, 27, 61"`
+    // the Tags and Attributes fields are stored as JSON in the items table
+    Tags      []string  `json:"tags" gorm:"type:json;serializer:json" faker:"-"`
+    Attributes map[string]any `json:"attributes" gorm:"type:json;serializer:json" faker:"-"`
+    // the Barcodes field is stored in the item_barcodes table
+    Barcodes  []ItemBarcode `json:"barcodes" gorm:"foreignKey:ItemID;constraint:-" faker:"-"`
+    // the Variants and TotalQuantity fields are filled when the product is returned
+    // the total quantity contains the quantity of the product and its variants
+    Variants  []Item    `json:"variants,omitempty" gorm:"-" faker:"-"`
+    TotalQuantity *int  `json:"total_quantity,omitempty" gorm:"-" faker:"-"`
+    // the OnHand, Reserved and Available fields are filled when the item is returned
+    // the available quantity is the quantity on hand that is not held by the active reservations
+    OnHand    int       `json:"on_hand" gorm:"-" faker:"-"`
+    Reserved  int       `json:"reserved" gorm:"-" faker:"-"`
+    Available int       `json:"available" gorm:"-" faker:"-"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
 }
\ No newline at end of file
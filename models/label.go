@@ -0,0 +1,18 @@
+package models
+
+//the formats of the item label
+const (
+	LabelFormatPNG = "png"
+	LabelFormatPDF = "pdf"
+)
+
+//LabelSheetRequest is used to print the labels of the items on a sheet
+//the label is printed again if the item ID is repeated
+type LabelSheetRequest struct {
+	ItemIDs []string `json:"item_ids" validate:"required,min=1,max=240,dive,required"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (labelSheetInput LabelSheetRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(labelSheetInput)
+}
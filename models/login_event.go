@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+//the types of the recorded login events
+const (
+	LoginEventFailed  = "login_failed"
+	LoginEventBlocked = "login_blocked"
+	LoginEventLockout = "lockout"
+	LoginEventUnlock  = "unlock"
+)
+
+//LoginEvent records a security relevant event of the login
+//these events are used to see the attack patterns
+type LoginEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type" gorm:"index"`
+	Email     string    `json:"email" gorm:"index"`
+	IPAddress string    `json:"ip_address" gorm:"index"`
+	UserID    string    `json:"user_id"`
+	Details   string    `json:"details"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+//LoginThrottle counts the failed login attempts for an account or an IP address
+//the key is prefixed with "account:" or "ip:"
+type LoginThrottle struct {
+	Key           string     `json:"key" gorm:"size:255;primaryKey"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	BlockedUntil  *time.Time `json:"blocked_until"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+//RecoveryCode is a one-time code that replaces the TOTP code
+//only the hash of the code is stored in the database
+type RecoveryCode struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id" gorm:"index"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+//TOTPEnrollment is returned when the user starts the TOTP enrollment
+type TOTPEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCodePNG  []byte `json:"qr_code_png"`
+}
+
+//RecoveryCodes is returned once when the TOTP enrollment is confirmed
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+//TOTPCodeRequest is used to confirm or disable the TOTP
+type TOTPCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (codeInput TOTPCodeRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(codeInput)
+}
+
+//MFALoginRequest is used to exchange the MFA token and the code for a token pair
+//the code can be a TOTP code or a recovery code
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (mfaInput MFALoginRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(mfaInput)
+}
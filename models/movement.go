@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//the types of the stock movements
+const (
+	MovementTypeReceipt    = "receipt"
+	MovementTypeIssue      = "issue"
+	MovementTypeAdjustment = "adjustment"
+	MovementTypeTransfer   = "transfer"
+	MovementTypeReturn     = "return"
+)
+
+//the reason codes of the movements that are recorded by the application
+const (
+	ReasonInitialStock   = "initial_stock"
+	ReasonItemUpdate     = "item_update"
+	ReasonStockCount     = "stock_count"
+	ReasonOpeningBalance = "opening_balance"
+	ReasonItemDeleted    = "item_deleted"
+)
+
+//ErrImmutableMovement is returned when the stock movement is updated or deleted
+var ErrImmutableMovement = errors.New("stock movements cannot be updated or deleted")
+
+//StockMovement is a change of the stock of the item at the location
+//the movements are never changed, a wrong movement is corrected with another movement
+type StockMovement struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id" gorm:"size:191;index"`
+	ItemID         string `json:"item_id" gorm:"size:191;index:idx_stock_movements_item_created"`
+	LocationID     string `json:"location_id" gorm:"size:191;index"`
+	Type           string `json:"type" gorm:"size:20"`
+	// the quantity is positive when the stock is added and negative when the stock is removed
+	Quantity int `json:"quantity"`
+	// the stock of the item at the location after the movement
+	Balance    int    `json:"balance"`
+	ReasonCode string `json:"reason_code" gorm:"size:50"`
+	// the document that caused the movement, for example the number of the purchase order
+	Reference string `json:"reference" gorm:"size:100"`
+	// the user is empty if the movement is recorded by the application
+	UserID    string    `json:"user_id" gorm:"size:191"`
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_stock_movements_item_created"`
+}
+
+//BeforeUpdate prevents the stock movement from being updated
+func (movement *StockMovement) BeforeUpdate(tx *gorm.DB) error {
+	return ErrImmutableMovement
+}
+
+//BeforeDelete prevents the stock movement from being deleted
+func (movement *StockMovement) BeforeDelete(tx *gorm.DB) error {
+	return ErrImmutableMovement
+}
+
+//StockMovementRequest is used to record the movement of the item
+type StockMovementRequest struct {
+	Type       string `json:"type" validate:"required,oneof=receipt issue adjustment transfer return"`
+	LocationID string `json:"location_id" validate:"required,uuid"`
+	// the location that receives the stock of the transfer
+	ToLocationID string `json:"to_location_id" validate:"required_if=Type transfer,omitempty,uuid"`
+	// the quantity of the adjustment is negative when the stock is removed
+	// the quantity of the other movements is always positive
+	Quantity   int    `json:"quantity" validate:"required"`
+	ReasonCode string `json:"reason_code" validate:"required_if=Type adjustment,max=50"`
+	Reference  string `json:"reference" validate:"max=100"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (movementInput StockMovementRequest) ValidateStruct() []*ErrorResponse {
+	var errors []*ErrorResponse = validateStruct(movementInput)
+
+	//only the adjustment can remove the stock with a negative quantity
+	if movementInput.Type != MovementTypeAdjustment && movementInput.Quantity < 0 {
+		errors = append(errors, &ErrorResponse{
+			ErrorMessage: "the quantity must be positive, only the adjustment can have a negative quantity",
+			Field:        "Quantity",
+		})
+	}
+
+	//the transfer must move the stock into another location
+	if movementInput.Type == MovementTypeTransfer && movementInput.ToLocationID == movementInput.LocationID {
+		errors = append(errors, &ErrorResponse{
+			ErrorMessage: "the stock must be transferred into another location",
+			Field:        "ToLocationID",
+		})
+	}
+
+	return errors
+}
+
+//MovementQuery is used to list the movements of the item
+//the dates are written in the YYYY-MM-DD format and both ends of the range are included
+type MovementQuery struct {
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Type       string `query:"type" validate:"omitempty,oneof=receipt issue adjustment transfer return"`
+	LocationID string `query:"location_id"`
+	Limit      int    `query:"limit" validate:"gte=0,lte=500"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (movementQuery MovementQuery) ValidateStruct() []*ErrorResponse {
+	return validateStruct(movementQuery)
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+//Organization is a tenant, every item belongs to one organization
+type Organization struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug" gorm:"unique"`
+	// the role of the current user, it is filled when the organizations of the user are listed
+	Role      string    `json:"role,omitempty" gorm:"->;-:migration"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//Membership links the user to the organization with a role
+//the role of the membership is used for the permissions inside the organization
+type Membership struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id" gorm:"size:191;uniqueIndex:idx_memberships_organization_user"`
+	UserID         string    `json:"user_id" gorm:"size:191;uniqueIndex:idx_memberships_organization_user;index"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+//OrganizationRequest is used to create a new organization
+type OrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Slug string `json:"slug" validate:"required,max=100,alphanum"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (organizationInput OrganizationRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(organizationInput)
+}
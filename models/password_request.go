@@ -0,0 +1,22 @@
+package models
+
+//ForgotPasswordRequest is used to request a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (forgotInput ForgotPasswordRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(forgotInput)
+}
+
+//ResetPasswordRequest is used to set a new password with the reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (resetInput ResetPasswordRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(resetInput)
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+//RefreshToken stores the hash of a refresh token issued to the user
+//all refresh tokens that are rotated from the same login share the same family
+type RefreshToken struct {
+	ID       string `json:"id"`
+	UserID   string `json:"user_id" gorm:"index"`
+	FamilyID string `json:"family_id" gorm:"index"`
+	// the organization that is selected for the session
+	OrganizationID string `json:"organization_id"`
+	TokenHash      string `json:"-" gorm:"unique"`
+	// the access token that is issued together with the refresh token
+	AccessTokenID        string     `json:"-" gorm:"index"`
+	AccessTokenExpiresAt time.Time  `json:"-"`
+	ExpiresAt            time.Time  `json:"expires_at"`
+	UsedAt               *time.Time `json:"used_at"`
+	RevokedAt            *time.Time `json:"revoked_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+//the statuses of the reservation
+//only the active reservation that is not expired holds the stock
+const (
+	ReservationStatusActive    = "active"
+	ReservationStatusReleased  = "released"
+	ReservationStatusFulfilled = "fulfilled"
+	ReservationStatusExpired   = "expired"
+)
+
+//ReasonReservationFulfilled is the reason code of the issue that fulfills the reservation
+const ReasonReservationFulfilled = "reservation_fulfilled"
+
+//Reservation holds the stock of the item for an order that is not shipped yet
+type Reservation struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id" gorm:"size:191;index"`
+	ItemID         string `json:"item_id" gorm:"size:191;index:idx_reservations_item_status"`
+	Quantity       int    `json:"quantity"`
+	// the order that the stock is reserved for, for example the number of the sales order
+	Reference  string     `json:"reference" gorm:"size:100"`
+	Status     string     `json:"status" gorm:"size:20;index:idx_reservations_item_status;index:idx_reservations_status_expires"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"index:idx_reservations_status_expires"`
+	CreatedBy  string     `json:"created_by" gorm:"size:191"`
+	ReleasedAt *time.Time `json:"released_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+//ReservationRequest is used to reserve the stock of the item
+type ReservationRequest struct {
+	ItemID    string `json:"item_id" validate:"required,uuid"`
+	Quantity  int    `json:"quantity" validate:"required,gt=0"`
+	Reference string `json:"reference" validate:"max=100"`
+	// the default expiry is used if the expiry is not provided
+	ExpiresInMinutes int `json:"expires_in_minutes" validate:"gte=0,lte=43200"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (reservationInput ReservationRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(reservationInput)
+}
+
+//FulfillReservationRequest is used to issue the reserved stock from the location
+type FulfillReservationRequest struct {
+	LocationID string `json:"location_id" validate:"required,uuid"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (fulfillInput FulfillReservationRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(fulfillInput)
+}
+
+//ReservationQuery is used to list the reservations
+type ReservationQuery struct {
+	ItemID string `query:"item_id"`
+	Status string `query:"status" validate:"omitempty,oneof=active released fulfilled expired"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (reservationQuery ReservationQuery) ValidateStruct() []*ErrorResponse {
+	return validateStruct(reservationQuery)
+}
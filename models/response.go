@@ -1,8 +1,25 @@
-package models
-
-//use to generate a response body
-type Response[T any] struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	Data    T      `json:"data"`
-}
\ No newline at end of file
+package models
+
+//use to generate a response body
+type Response[T any] struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+//Pagination describes the page of a paginated response
+//the offset pagination uses page and per_page
+//the cursor pagination uses limit and next_cursor
+type Pagination struct {
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int64  `json:"total"`
+}
+
+//use to generate a response body with pagination
+type PaginatedResponse[T any] struct {
+	Response[T]
+	Pagination Pagination `json:"pagination"`
+}
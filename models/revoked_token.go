@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+//RevokedToken is an entry of the JWT denylist
+//the entry can be removed once the JWT token is expired
+type RevokedToken struct {
+	TokenID   string    `json:"token_id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id" gorm:"index"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,105 @@
+package models
+
+//the roles that can be assigned to the user
+const (
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleClerk   = "clerk"
+	RoleViewer  = "viewer"
+)
+
+//the permissions that are checked by the routes
+const (
+	PermissionItemsRead   = "items:read"
+	PermissionItemsCreate = "items:create"
+	PermissionItemsUpdate = "items:update"
+	PermissionItemsDelete = "items:delete"
+	PermissionUsersManage = "users:manage"
+	// the permission to invite and manage the members of the organization
+	PermissionMembersManage = "members:manage"
+	// the permission to create, update and delete the categories of the organization
+	PermissionCategoriesManage = "categories:manage"
+	// the permission to create, update and delete the warehouses and their locations
+	PermissionWarehousesManage = "warehouses:manage"
+)
+
+//RolePermissions is the permission matrix for every role
+var RolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermissionItemsRead,
+		PermissionItemsCreate,
+		PermissionItemsUpdate,
+		PermissionItemsDelete,
+		PermissionUsersManage,
+		PermissionMembersManage,
+		PermissionCategoriesManage,
+		PermissionWarehousesManage,
+	},
+	RoleManager: {
+		PermissionItemsRead,
+		PermissionItemsCreate,
+		PermissionItemsUpdate,
+		PermissionItemsDelete,
+		PermissionCategoriesManage,
+		PermissionWarehousesManage,
+	},
+	RoleClerk: {
+		PermissionItemsRead,
+		PermissionItemsCreate,
+		PermissionItemsUpdate,
+	},
+	RoleViewer: {
+		PermissionItemsRead,
+	},
+}
+
+//TenantPermissions are checked against the role of the membership
+//the other permissions are checked against the role of the user
+var TenantPermissions = []string{
+	PermissionItemsRead,
+	PermissionItemsCreate,
+	PermissionItemsUpdate,
+	PermissionItemsDelete,
+	PermissionMembersManage,
+	PermissionCategoriesManage,
+	PermissionWarehousesManage,
+}
+
+//IsTenantPermission returns true if the permission is granted inside an organization
+func IsTenantPermission(permission string) bool {
+	for _, tenantPermission := range TenantPermissions {
+		if tenantPermission == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+//HasPermission returns true if the role is granted the permission
+func HasPermission(role string, permission string) bool {
+	for _, granted := range RolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+//IsValidRole returns true if the role is defined in the permission matrix
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+//IsValidPermission returns true if the permission is granted to any role
+func IsValidPermission(permission string) bool {
+	for role := range RolePermissions {
+		if HasPermission(role, permission) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+//SigningKey is a key pair used to sign the JWT tokens
+//the key signs new tokens until ActiveUntil and can verify tokens until ExpiresAt
+type SigningKey struct {
+	ID          string    `json:"id"`
+	Algorithm   string    `json:"algorithm"`
+	PrivateKey  string    `json:"-" gorm:"type:text"`
+	PublicKey   string    `json:"public_key" gorm:"type:text"`
+	ActiveUntil time.Time `json:"active_until"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+//TokenPair contains the access token and the refresh token for the user
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+//LoginResult is returned by the login
+//if the second factor is required, the MFA token is returned instead of the token pair
+type LoginResult struct {
+	*TokenPair
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
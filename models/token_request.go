@@ -0,0 +1,11 @@
+package models
+
+//RefreshRequest is used to exchange a refresh token for a new token pair
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (refreshInput RefreshRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(refreshInput)
+}
@@ -0,0 +1,130 @@
+package models
+
+import "time"
+
+//the statuses of the transfer order
+//the draft is shipped or cancelled, the shipped transfer is received or cancelled
+const (
+	TransferStatusDraft     = "draft"
+	TransferStatusShipped   = "shipped"
+	TransferStatusReceived  = "received"
+	TransferStatusCancelled = "cancelled"
+)
+
+//the reason codes of the transfer movements
+const (
+	ReasonTransferShipped   = "transfer_shipped"
+	ReasonTransferReceived  = "transfer_received"
+	ReasonTransferCancelled = "transfer_cancelled"
+)
+
+//TransferOrder moves the stock of the items from one location to another
+//the stock is in transit between the shipment and the receipt
+type TransferOrder struct {
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id" gorm:"size:191;index"`
+	FromLocationID string         `json:"from_location_id" gorm:"size:191;index"`
+	ToLocationID   string         `json:"to_location_id" gorm:"size:191;index"`
+	Status         string         `json:"status" gorm:"size:20;index"`
+	Note           string         `json:"note"`
+	CreatedBy      string         `json:"created_by" gorm:"size:191"`
+	Lines          []TransferLine `json:"lines" gorm:"foreignKey:TransferOrderID;constraint:-"`
+	ShippedAt      *time.Time     `json:"shipped_at"`
+	ReceivedAt     *time.Time     `json:"received_at"`
+	CancelledAt    *time.Time     `json:"cancelled_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+//TransferLine is the quantity of the item in the transfer order
+type TransferLine struct {
+	ID               string `json:"id"`
+	TransferOrderID  string `json:"transfer_order_id" gorm:"size:191;index"`
+	ItemID           string `json:"item_id" gorm:"size:191;index"`
+	Quantity         int    `json:"quantity"`
+	ReceivedQuantity int    `json:"received_quantity"`
+	// the quantity that is shipped but not received yet
+	InTransit int `json:"in_transit" gorm:"-"`
+	// the quantity that is missing when the transfer is received
+	Discrepancy int `json:"discrepancy" gorm:"-"`
+}
+
+//SetQuantities fills the in-transit and missing quantities of the lines from the status of the transfer
+func (transfer *TransferOrder) SetQuantities() {
+	for i := range transfer.Lines {
+		var remaining int = transfer.Lines[i].Quantity - transfer.Lines[i].ReceivedQuantity
+
+		transfer.Lines[i].InTransit = 0
+		transfer.Lines[i].Discrepancy = 0
+
+		switch transfer.Status {
+		case TransferStatusShipped:
+			transfer.Lines[i].InTransit = remaining
+		case TransferStatusReceived:
+			transfer.Lines[i].Discrepancy = remaining
+		}
+	}
+}
+
+//TransferLineRequest is the quantity of the item to be transferred or received
+type TransferLineRequest struct {
+	ItemID   string `json:"item_id" validate:"required,uuid"`
+	Quantity int    `json:"quantity" validate:"required,gt=0"`
+}
+
+//TransferOrderRequest is used to create the transfer order
+type TransferOrderRequest struct {
+	FromLocationID string                `json:"from_location_id" validate:"required,uuid"`
+	ToLocationID   string                `json:"to_location_id" validate:"required,uuid"`
+	Note           string                `json:"note" validate:"max=500"`
+	Lines          []TransferLineRequest `json:"lines" validate:"required,min=1,max=100,unique=ItemID,dive"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (transferInput TransferOrderRequest) ValidateStruct() []*ErrorResponse {
+	var errors []*ErrorResponse = validateStruct(transferInput)
+
+	//the stock must be transferred into another location
+	if transferInput.FromLocationID != "" && transferInput.FromLocationID == transferInput.ToLocationID {
+		errors = append(errors, &ErrorResponse{
+			ErrorMessage: "the stock must be transferred into another location",
+			Field:        "ToLocationID",
+		})
+	}
+
+	return errors
+}
+
+//TransferReceiptRequest is used to receive the shipped transfer
+//the transfer can be received in several parts, the complete receipt closes the transfer
+//and the quantity that is still in transit is reported as the discrepancy
+type TransferReceiptRequest struct {
+	Lines    []TransferLineRequest `json:"lines" validate:"max=100,unique=ItemID,dive"`
+	Complete bool                  `json:"complete"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (receiptInput TransferReceiptRequest) ValidateStruct() []*ErrorResponse {
+	var errors []*ErrorResponse = validateStruct(receiptInput)
+
+	//the receipt must receive an item or close the transfer
+	if len(receiptInput.Lines) == 0 && !receiptInput.Complete {
+		errors = append(errors, &ErrorResponse{
+			ErrorMessage: "the receipt must contain lines or complete the transfer",
+			Field:        "Lines",
+		})
+	}
+
+	return errors
+}
+
+//TransferQuery is used to list the transfer orders
+type TransferQuery struct {
+	Status string `query:"status" validate:"omitempty,oneof=draft shipped received cancelled"`
+	ItemID string `query:"item_id"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (transferQuery TransferQuery) ValidateStruct() []*ErrorResponse {
+	return validateStruct(transferQuery)
+}
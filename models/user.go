@@ -1,15 +1,30 @@
-package models
-
-import "time"
-
-//CREATE a DB migration
-type User struct {
-	// the ID field will be filled with uuid data from the faker
-   ID        string    `json:"id" faker:"uuid_hyphenated"`
-	// the Email field will be filled with email data from the faker
-   Email     string    `json:"email" gorm:"unique" faker:"email"`
-   // the Password field will be filled with password data from the faker
-   Password  string    `json:"password" faker:"password"`
-   CreatedAt time.Time `json:"created_at"`
-   UpdatedAt time.Time `json:"updated_at"`
-}
+package models
+
+import "time"
+
+//CREATE a DB migration
+type User struct {
+	// the ID field will be filled with uuid data from the faker
+   ID        string    `json:"id" faker:"uuid_hyphenated"`
+	// the Email field will be filled with email data from the faker
+   Email     string    `json:"email" gorm:"unique" faker:"email"`
+   // the Password field will be filled with password data from the faker
+   // the password hash is never serialized into the responses
+   Password  string    `json:"-" faker:"password"`
+   // the Role field decides the permissions of the user
+   Role      string    `json:"role" gorm:"default:viewer" faker:"-"`
+   // the profile fields can be changed by the user
+   DisplayName string `json:"display_name" faker:"-"`
+   Locale      string `json:"locale" gorm:"default:en" faker:"-"`
+   Timezone    string `json:"timezone" gorm:"default:UTC" faker:"-"`
+   // the EmailVerifiedAt field is filled when the user verifies the email
+   EmailVerifiedAt *time.Time `json:"email_verified_at" faker:"-"`
+   // the TOTP fields are used for the two-factor authentication
+   TOTPSecret       string     `json:"-" faker:"-"`
+   TOTPEnabledAt    *time.Time `json:"totp_enabled_at" faker:"-"`
+   TOTPLastUsedStep int64      `json:"-" faker:"-"`
+   // the DisabledAt field is filled when the account is disabled by an admin
+   DisabledAt *time.Time `json:"disabled_at" faker:"-"`
+   CreatedAt time.Time `json:"created_at"`
+   UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+//UserQuery is used to list the users
+type UserQuery struct {
+	Page    int    `query:"page" validate:"gte=0"`
+	PerPage int    `query:"per_page" validate:"gte=0,lte=100"`
+	Search  string `query:"q"`
+	Role    string `query:"role" validate:"omitempty,oneof=admin manager clerk viewer"`
+	Status  string `query:"status" validate:"omitempty,oneof=active disabled"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (userQuery UserQuery) ValidateStruct() []*ErrorResponse {
+	return validateStruct(userQuery)
+}
+
+//UpdateUserRequest is used by the admin to update the user
+//only the provided fields are updated
+type UpdateUserRequest struct {
+	Role     *string `json:"role" validate:"omitempty,oneof=admin manager clerk viewer"`
+	Disabled *bool   `json:"disabled"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (userInput UpdateUserRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(userInput)
+}
@@ -1,29 +1,53 @@
-package models
-
-import "github.com/go-playground/validator/v10"
-
-type UserRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
-}
-
-//ValidateStruct returns validation errors if validation failed
-func (userInput UserRequest) ValidateStruct() []*ErrorResponse {
-	var errors []*ErrorResponse
-	validate := validator.New()
-	err := validate.Struct(userInput)
-	if err != nil {
-		for _, err := range err.(validator.ValidationErrors) {
-			var element ErrorResponse
-			element.ErrorMessage = getErrorMessage(err)
-			element.Field = err.Field()
-			errors = append(errors, &element)
-		}
-	}
-
-	return errors
-}
-
-/*
-In the code above, ValidateStruct() is added in UserRequest to perform validation. This function is also used in ItemRequest.
-*/ 
+package models
+
+import "github.com/go-playground/validator/v10"
+
+type UserRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (userInput UserRequest) ValidateStruct() []*ErrorResponse {
+	var errors []*ErrorResponse
+	validate := validator.New()
+	err := validate.Struct(userInput)
+	if err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			var element ErrorResponse
+			element.ErrorMessage = getErrorMessage(err)
+			element.Field = err.Field()
+			errors = append(errors, &element)
+		}
+	}
+
+	return errors
+}
+
+//UpdateProfileRequest is used by the user to update their own profile
+//only the provided fields are updated
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name" validate:"omitempty,max=100"`
+	Locale      *string `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	Timezone    *string `json:"timezone" validate:"omitempty,timezone"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (profileInput UpdateProfileRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(profileInput)
+}
+
+//ChangePasswordRequest is used by the user to change their own password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (passwordInput ChangePasswordRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(passwordInput)
+}
+
+/*
+In the code above, ValidateStruct() is added in UserRequest to perform validation. This function is also used in ItemRequest.
+*/ 
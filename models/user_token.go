@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+//the purposes of the one-time user tokens
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+//UserToken is a single-use token that is sent to the user by email
+//only the hash of the token is stored in the database
+type UserToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id" gorm:"index"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-" gorm:"unique"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
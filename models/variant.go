@@ -0,0 +1,40 @@
+package models
+
+import "strconv"
+
+//MaxVariants is the maximum number of the variants of the product
+const MaxVariants = 500
+
+//VariantAxis is a dimension of the product variants, for example size or color
+type VariantAxis struct {
+	Name   string   `json:"name" validate:"required,max=50"`
+	Values []string `json:"values" validate:"required,min=1,max=50,unique,dive,required,max=50"`
+}
+
+//VariantMatrixRequest is used to generate the variants of the product
+//a variant is created for every combination of the axis values that does not exist yet
+type VariantMatrixRequest struct {
+	Axes []VariantAxis `json:"axes" validate:"required,min=1,max=3,unique=Name,dive"`
+	// the price of the product is used if the price is not provided
+	Price *int `json:"price" validate:"omitempty,gt=0"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (variantInput VariantMatrixRequest) ValidateStruct() []*ErrorResponse {
+	var errors []*ErrorResponse = validateStruct(variantInput)
+
+	//limit the number of the generated variants
+	var combinations int = 1
+	for _, axis := range variantInput.Axes {
+		combinations *= len(axis.Values)
+	}
+
+	if combinations > MaxVariants {
+		errors = append(errors, &ErrorResponse{
+			ErrorMessage: "the axes cannot generate more than " + strconv.Itoa(MaxVariants) + " variants",
+			Field:        "Axes",
+		})
+	}
+
+	return errors
+}
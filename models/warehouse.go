@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+//Warehouse is a building of the organization where the items are stocked
+type Warehouse struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id" gorm:"size:191;uniqueIndex:idx_warehouses_organization_code"`
+	Code           string    `json:"code" gorm:"size:50;uniqueIndex:idx_warehouses_organization_code"`
+	Name           string    `json:"name"`
+	Address        string    `json:"address"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+//Location is a storage location inside the warehouse, for example a bin of a shelf
+type Location struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id" gorm:"size:191;index"`
+	WarehouseID    string    `json:"warehouse_id" gorm:"size:191;uniqueIndex:idx_locations_warehouse_code"`
+	Code           string    `json:"code" gorm:"size:50;uniqueIndex:idx_locations_warehouse_code"`
+	Name           string    `json:"name"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+//StockLevel is the quantity of the item at the location
+//the quantity of the item is the sum of its stock levels
+type StockLevel struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id" gorm:"size:191;index"`
+	ItemID         string    `json:"item_id" gorm:"size:191;uniqueIndex:idx_stock_levels_item_location"`
+	LocationID     string    `json:"location_id" gorm:"size:191;uniqueIndex:idx_stock_levels_item_location;index"`
+	Quantity       int       `json:"quantity"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+//LocationStock is the stock of the item at the location with the location details
+type LocationStock struct {
+	LocationID    string `json:"location_id"`
+	LocationCode  string `json:"location_code"`
+	LocationName  string `json:"location_name"`
+	WarehouseID   string `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name"`
+	Quantity      int    `json:"quantity"`
+	// the sum of the movements of the item at the location
+	LedgerQuantity int `json:"ledger_quantity"`
+}
+
+//ItemStock is the stock of the item broken down by location
+type ItemStock struct {
+	ItemID   string `json:"item_id"`
+	Quantity int    `json:"quantity"`
+	// the quantity of the shipped transfers that is not received yet
+	InTransit int             `json:"in_transit"`
+	Locations []LocationStock `json:"locations"`
+	// the stock of every location matches the sum of its movements
+	Reconciled bool `json:"reconciled"`
+}
+
+//WarehouseRequest is used to create or update the warehouse
+type WarehouseRequest struct {
+	Code    string `json:"code" validate:"required,max=50,printascii"`
+	Name    string `json:"name" validate:"required,max=100"`
+	Address string `json:"address" validate:"max=500"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (warehouseInput WarehouseRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(warehouseInput)
+}
+
+//LocationRequest is used to create or update the location of the warehouse
+type LocationRequest struct {
+	Code string `json:"code" validate:"required,max=50,printascii"`
+	Name string `json:"name" validate:"max=100"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (locationInput LocationRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(locationInput)
+}
+
+//StockLevelRequest is used to set the counted quantity of the item at the location
+type StockLevelRequest struct {
+	Quantity int `json:"quantity" validate:"gte=0"`
+}
+
+//ValidateStruct returns validation errors if validation failed
+func (stockInput StockLevelRequest) ValidateStruct() []*ErrorResponse {
+	return validateStruct(stockInput)
+}
@@ -1,25 +1,125 @@
-package routes
-
-import (
-	"github.com/gofiber/fiber/v2"
-	"inventory-project-testing/handlers"
-	"inventory-project-testing/middlewares"
-)
-
-func SetupRoutes(app *fiber.App) {
-	// public routes
-	var publicRoutes fiber.Router = app.Group("/api/v1")
-
-	publicRoutes.Post("/signup", handlers.Signup)
-	publicRoutes.Post("/login", handlers.Login)
-	publicRoutes.Get("/items", handlers.GetAllItems)
-	publicRoutes.Get("/items/:id", handlers.GetItemByID)
-
-	// private routes, authentication is required
-	// the middleware is added
-	var privateRoutes fiber.Router = app.Group("/api/v1", middlewares.CreateMiddleware())
-
-	privateRoutes.Post("/items", handlers.CreateItem)
-	privateRoutes.Put("/items/:id", handlers.UpdateItem)
-	privateRoutes.Delete("/items/:id", handlers.DeleteItem)
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"inventory-project-testing/handlers"
+	"inventory-project-testing/middlewares"
+	"inventory-project-testing/models"
+)
+
+func SetupRoutes(app *fiber.App) {
+	// public keys to verify the JWT tokens
+	app.Get("/.well-known/jwks.json", handlers.GetJWKS)
+
+	// public routes
+	var publicRoutes fiber.Router = app.Group("/api/v1")
+
+	publicRoutes.Post("/signup", handlers.Signup)
+	publicRoutes.Post("/login", handlers.Login)
+	publicRoutes.Post("/login/mfa", handlers.LoginWithMFA)
+	publicRoutes.Post("/token/refresh", handlers.RefreshToken)
+	publicRoutes.Post("/password/forgot", handlers.ForgotPassword)
+	publicRoutes.Post("/password/reset", handlers.ResetPassword)
+	publicRoutes.Get("/verify-email", handlers.VerifyEmail)
+	publicRoutes.Post("/invitations/accept", handlers.AcceptInvitation)
+	publicRoutes.Get("/oidc/login", handlers.StartOIDCLogin)
+	publicRoutes.Get("/oidc/callback", handlers.CompleteOIDCLogin)
+
+	// private routes, authentication is required
+	// the middleware is added
+	var privateRoutes fiber.Router = app.Group("/api/v1", middlewares.CreateMiddleware())
+
+	privateRoutes.Post("/logout", handlers.Logout)
+	privateRoutes.Post("/users/:id/sessions/revoke-all", handlers.RevokeAllSessions)
+	privateRoutes.Post("/verify-email/resend", handlers.ResendVerificationEmail)
+
+	privateRoutes.Get("/me", handlers.GetProfile)
+	privateRoutes.Patch("/me", handlers.UpdateProfile)
+	privateRoutes.Post("/me/password", handlers.ChangePassword)
+
+	privateRoutes.Post("/me/mfa/totp", handlers.EnrollTOTP)
+	privateRoutes.Post("/me/mfa/totp/confirm", handlers.ConfirmTOTP)
+	privateRoutes.Delete("/me/mfa/totp", handlers.DisableTOTP)
+
+	privateRoutes.Get("/users", middlewares.RequirePermission(models.PermissionUsersManage), handlers.GetUsers)
+	privateRoutes.Get("/users/:id", middlewares.RequirePermission(models.PermissionUsersManage), handlers.GetUserByID)
+	privateRoutes.Patch("/users/:id", middlewares.RequirePermission(models.PermissionUsersManage), handlers.UpdateUser)
+	privateRoutes.Delete("/users/:id", middlewares.RequirePermission(models.PermissionUsersManage), handlers.DeleteUser)
+	privateRoutes.Post("/users/:id/unlock", middlewares.RequirePermission(models.PermissionUsersManage), handlers.UnlockUser)
+	privateRoutes.Get("/login-events", middlewares.RequirePermission(models.PermissionUsersManage), handlers.GetLoginEvents)
+
+	privateRoutes.Get("/organizations", handlers.GetOrganizations)
+	privateRoutes.Post("/organizations", middlewares.RequireVerifiedEmail(), handlers.CreateOrganization)
+	privateRoutes.Post("/organizations/:id/switch", handlers.SwitchOrganization)
+
+	privateRoutes.Post("/invitations", middlewares.RequirePermission(models.PermissionMembersManage), handlers.CreateInvitation)
+	privateRoutes.Get("/invitations", middlewares.RequirePermission(models.PermissionMembersManage), handlers.GetInvitations)
+	privateRoutes.Delete("/invitations/:id", middlewares.RequirePermission(models.PermissionMembersManage), handlers.RevokeInvitation)
+
+	privateRoutes.Post("/api-keys", handlers.CreateAPIKey)
+	privateRoutes.Get("/api-keys", handlers.GetAPIKeys)
+	privateRoutes.Delete("/api-keys/:id", handlers.RevokeAPIKey)
+
+	// category routes, the categories are only visible inside the organization of the user
+	var categoryRoutes fiber.Router = privateRoutes.Group("/categories", middlewares.RequireVerifiedEmail())
+
+	categoryRoutes.Get("/", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetCategories)
+	categoryRoutes.Get("/:id", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetCategoryByID)
+	categoryRoutes.Post("/", middlewares.RequirePermission(models.PermissionCategoriesManage), handlers.CreateCategory)
+	categoryRoutes.Put("/:id", middlewares.RequirePermission(models.PermissionCategoriesManage), handlers.UpdateCategory)
+	categoryRoutes.Delete("/:id", middlewares.RequirePermission(models.PermissionCategoriesManage), handlers.DeleteCategory)
+	categoryRoutes.Get("/:id/attributes", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetCategoryAttributes)
+	categoryRoutes.Post("/:id/attributes", middlewares.RequirePermission(models.PermissionCategoriesManage), handlers.CreateAttributeDefinition)
+	categoryRoutes.Put("/:id/attributes/:attributeId", middlewares.RequirePermission(models.PermissionCategoriesManage), handlers.UpdateAttributeDefinition)
+	categoryRoutes.Delete("/:id/attributes/:attributeId", middlewares.RequirePermission(models.PermissionCategoriesManage), handlers.DeleteAttributeDefinition)
+
+	// warehouse routes, the warehouses are only visible inside the organization of the user
+	var warehouseRoutes fiber.Router = privateRoutes.Group("/warehouses", middlewares.RequireVerifiedEmail())
+
+	warehouseRoutes.Get("/", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetWarehouses)
+	warehouseRoutes.Post("/", middlewares.RequirePermission(models.PermissionWarehousesManage), handlers.CreateWarehouse)
+	warehouseRoutes.Put("/:id", middlewares.RequirePermission(models.PermissionWarehousesManage), handlers.UpdateWarehouse)
+	warehouseRoutes.Delete("/:id", middlewares.RequirePermission(models.PermissionWarehousesManage), handlers.DeleteWarehouse)
+	warehouseRoutes.Get("/:id/locations", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetLocations)
+	warehouseRoutes.Post("/:id/locations", middlewares.RequirePermission(models.PermissionWarehousesManage), handlers.CreateLocation)
+	warehouseRoutes.Put("/:id/locations/:locationId", middlewares.RequirePermission(models.PermissionWarehousesManage), handlers.UpdateLocation)
+	warehouseRoutes.Delete("/:id/locations/:locationId", middlewares.RequirePermission(models.PermissionWarehousesManage), handlers.DeleteLocation)
+
+	// transfer routes, the transfer orders move the stock between the locations of the organization
+	var transferRoutes fiber.Router = privateRoutes.Group("/transfers", middlewares.RequireVerifiedEmail())
+
+	transferRoutes.Get("/", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetTransferOrders)
+	transferRoutes.Get("/:id", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetTransferOrderByID)
+	transferRoutes.Post("/", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.CreateTransferOrder)
+	transferRoutes.Post("/:id/ship", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.ShipTransferOrder)
+	transferRoutes.Post("/:id/receive", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.ReceiveTransferOrder)
+	transferRoutes.Post("/:id/cancel", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.CancelTransferOrder)
+
+	// reservation routes, the reservations hold the stock of the items for the orders that are not shipped yet
+	var reservationRoutes fiber.Router = privateRoutes.Group("/reservations", middlewares.RequireVerifiedEmail())
+
+	reservationRoutes.Get("/", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetReservations)
+	reservationRoutes.Post("/", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.CreateReservation)
+	reservationRoutes.Post("/:id/release", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.ReleaseReservation)
+	reservationRoutes.Post("/:id/fulfill", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.FulfillReservation)
+
+	// item routes, the email of the user must be verified
+	// the items are only visible inside the organization of the user
+	var itemRoutes fiber.Router = privateRoutes.Group("/items", middlewares.RequireVerifiedEmail())
+
+	itemRoutes.Get("/", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetAllItems)
+	itemRoutes.Get("/search", middlewares.RequirePermission(models.PermissionItemsRead), handlers.SearchItems)
+	itemRoutes.Get("/suggest", middlewares.RequirePermission(models.PermissionItemsRead), handlers.SuggestItems)
+	itemRoutes.Get("/by-barcode/:code", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetItemByBarcode)
+	itemRoutes.Get("/:id", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetItemByID)
+	itemRoutes.Get("/:id/label", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetItemLabel)
+	itemRoutes.Get("/:id/stock", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetItemStock)
+	itemRoutes.Put("/:id/stock/:locationId", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.SetItemStock)
+	itemRoutes.Get("/:id/movements", middlewares.RequirePermission(models.PermissionItemsRead), handlers.GetItemMovements)
+	itemRoutes.Post("/:id/movements", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.CreateItemMovement)
+	itemRoutes.Post("/:id/variants", middlewares.RequirePermission(models.PermissionItemsCreate), handlers.GenerateVariants)
+	itemRoutes.Post("/labels", middlewares.RequirePermission(models.PermissionItemsRead), handlers.CreateItemLabelSheet)
+	itemRoutes.Post("/", middlewares.RequirePermission(models.PermissionItemsCreate), handlers.CreateItem)
+	itemRoutes.Put("/:id", middlewares.RequirePermission(models.PermissionItemsUpdate), handlers.UpdateItem)
+	itemRoutes.Delete("/:id", middlewares.RequirePermission(models.PermissionItemsDelete), handlers.DeleteItem)
 }
\ No newline at end of file
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+)
+
+//apiKeyPrefix is added in front of every API key
+//this prefix makes the API keys easy to recognize
+const apiKeyPrefix = "inv_"
+
+//CreateAPIKey returns a new API key for the user
+//the key can only access the given organization
+//the plain key is only available in the returned value
+func CreateAPIKey(user models.User, organizationID string, apiKeyInput models.APIKeyRequest) (models.CreatedAPIKey, error) {
+	//generate a random secret for the key
+	secret, err := utils.GenerateRandomToken()
+	if err != nil {
+		return models.CreatedAPIKey{}, err
+	}
+
+	var key string = apiKeyPrefix + secret
+
+	//create a new API key object
+	//only the hash of the key is stored into the database
+	var apiKey models.APIKey = models.APIKey{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		OrganizationID: organizationID,
+		Name:      apiKeyInput.Name,
+		Prefix:    key[:len(apiKeyPrefix)+6],
+		KeyHash:   utils.HashToken(key),
+		Scopes:    strings.Join(apiKeyInput.Scopes, " "),
+		ExpiresAt: apiKeyInput.ExpiresAt,
+	}
+
+	//insert the API key into the database
+	if err := database.DB.Create(&apiKey).Error; err != nil {
+		return models.CreatedAPIKey{}, err
+	}
+
+	//return the API key together with the plain key
+	return models.CreatedAPIKey{
+		APIKey: apiKey,
+		Key:    key,
+	}, nil
+}
+
+//GetAPIKeys returns the API keys of the user
+func GetAPIKeys(userID string) []models.APIKey {
+	// create a variable to store API keys data
+	var apiKeys []models.APIKey = []models.APIKey{}
+
+	// get all API keys of the user order by created_at
+	database.DB.Where("user_id = ?", userID).Order("created_at desc").Find(&apiKeys)
+
+	// return the API keys
+	return apiKeys
+}
+
+//RevokeAPIKey revokes the API key of the user
+func RevokeAPIKey(userID string, id string) error {
+	//revoke the API key if the key belongs to the user
+	result := database.DB.Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	//if the API key is not found, return an error
+	if result.RowsAffected == 0 {
+		return errors.New("api key not found")
+	}
+
+	return nil
+}
+
+//AuthenticateAPIKey returns the API key and its owner based on the given key
+func AuthenticateAPIKey(key string) (models.APIKey, models.User, error) {
+	//create a variable called "apiKey"
+	var apiKey models.APIKey
+
+	//find the API key based on its hash
+	result := database.DB.First(&apiKey, "key_hash = ?", utils.HashToken(key))
+
+	//if the API key is not found or revoked, return the error
+	if result.RowsAffected == 0 || apiKey.RevokedAt != nil {
+		return models.APIKey{}, models.User{}, errors.New("invalid api key")
+	}
+
+	var now time.Time = time.Now()
+
+	//if the API key is expired, return the error
+	if apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt) {
+		return models.APIKey{}, models.User{}, errors.New("api key is expired")
+	}
+
+	//find the owner of the API key
+	user, err := GetUserByID(apiKey.UserID)
+	if err != nil {
+		return models.APIKey{}, models.User{}, errors.New("invalid api key")
+	}
+
+	//if the owner is disabled, the API key cannot be used
+	if user.DisabledAt != nil {
+		return models.APIKey{}, models.User{}, ErrAccountDisabled
+	}
+
+	//record the last usage of the API key
+	database.DB.Model(&apiKey).Update("last_used_at", now)
+
+	return apiKey, user, nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//ErrAttributeNotFound is returned when the attribute is not defined by the category
+var ErrAttributeNotFound = errors.New("attribute not found")
+
+//ErrDuplicateAttribute is returned when the attribute name is already defined in the category tree
+var ErrDuplicateAttribute = errors.New("attribute with the same name is already defined by the category, its ancestors or its descendants")
+
+//getAttributeDefinitions returns the attributes defined by the categories
+func getAttributeDefinitions(tx *gorm.DB, organizationID string, categoryIDs []string) []models.AttributeDefinition {
+	var definitions []models.AttributeDefinition = []models.AttributeDefinition{}
+
+	if len(categoryIDs) > 0 {
+		tx.Where("organization_id = ? AND category_id IN ?", organizationID, categoryIDs).Order("name asc").Find(&definitions)
+	}
+
+	return definitions
+}
+
+//checkAttributeNames returns an error if the names are defined by the categories
+//the attribute with the ID is not checked so it can keep its name
+func checkAttributeNames(tx *gorm.DB, organizationID string, categoryIDs []string, names []string, id string) error {
+	if len(categoryIDs) == 0 || len(names) == 0 {
+		return nil
+	}
+
+	var count int64
+	tx.Model(&models.AttributeDefinition{}).
+		Where("organization_id = ? AND category_id IN ? AND name IN ? AND id <> ?", organizationID, categoryIDs, names, id).
+		Count(&count)
+
+	if count > 0 {
+		return ErrDuplicateAttribute
+	}
+
+	return nil
+}
+
+//GetAttributeDefinitions returns the attributes of the items in the category
+//the attributes of the ancestor categories are included, no attribute is returned without category
+func GetAttributeDefinitions(organizationID string, categoryID *string) []models.AttributeDefinition {
+	if categoryID == nil {
+		return []models.AttributeDefinition{}
+	}
+
+	var categoryIDs []string = getCategoryAncestorIDs(getCategories(database.DB, organizationID), *categoryID)
+	return getAttributeDefinitions(database.DB, organizationID, categoryIDs)
+}
+
+//GetCategoryAttributes returns the attributes of the category including the inherited attributes
+func GetCategoryAttributes(organizationID string, categoryID string) ([]models.AttributeDefinition, error) {
+	if _, err := GetCategoryByID(organizationID, categoryID); err != nil {
+		return nil, err
+	}
+
+	return GetAttributeDefinitions(organizationID, &categoryID), nil
+}
+
+//CreateAttributeDefinition defines a new attribute for the items in the category
+//the name cannot be defined again by the ancestors or the descendants of the category
+func CreateAttributeDefinition(organizationID string, categoryID string, attributeInput models.AttributeDefinitionRequest) (models.AttributeDefinition, error) {
+	var definition models.AttributeDefinition = models.AttributeDefinition{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		CategoryID:     categoryID,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		return saveAttributeDefinition(tx, &definition, attributeInput)
+	})
+
+	if err != nil {
+		return models.AttributeDefinition{}, err
+	}
+
+	return definition, nil
+}
+
+//UpdateAttributeDefinition updates the attribute of the category
+//the values of the existing items are not validated again
+func UpdateAttributeDefinition(organizationID string, categoryID string, id string, attributeInput models.AttributeDefinitionRequest) (models.AttributeDefinition, error) {
+	var definition models.AttributeDefinition
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		if tx.Limit(1).Find(&definition, "organization_id = ? AND category_id = ? AND id = ?", organizationID, categoryID, id).RowsAffected == 0 {
+			return ErrAttributeNotFound
+		}
+
+		definition.UpdatedAt = time.Now()
+
+		return saveAttributeDefinition(tx, &definition, attributeInput)
+	})
+
+	if err != nil {
+		return models.AttributeDefinition{}, err
+	}
+
+	return definition, nil
+}
+
+//saveAttributeDefinition saves the attribute if its name is not used in the category tree
+func saveAttributeDefinition(tx *gorm.DB, definition *models.AttributeDefinition, attributeInput models.AttributeDefinitionRequest) error {
+	var categories []models.Category = getCategories(tx, definition.OrganizationID)
+
+	//the ancestors contain the category itself
+	var categoryIDs []string = getCategoryAncestorIDs(categories, definition.CategoryID)
+	if len(categoryIDs) == 0 {
+		return ErrCategoryNotFound
+	}
+
+	categoryIDs = append(categoryIDs, getCategoryDescendantIDs(categories, definition.CategoryID)...)
+
+	//if the name is already used, return the error
+	if err := checkAttributeNames(tx, definition.OrganizationID, categoryIDs, []string{attributeInput.Name}, definition.ID); err != nil {
+		return err
+	}
+
+	definition.Name = attributeInput.Name
+	definition.Type = attributeInput.Type
+	definition.Required = attributeInput.Required
+	definition.Options = attributeInput.Options
+	definition.Min = attributeInput.Min
+	definition.Max = attributeInput.Max
+	definition.Pattern = attributeInput.Pattern
+
+	if definition.Options == nil {
+		definition.Options = []string{}
+	}
+
+	return tx.Save(definition).Error
+}
+
+//DeleteAttributeDefinition deletes the attribute of the category
+//the values of the existing items are kept
+func DeleteAttributeDefinition(organizationID string, categoryID string, id string) error {
+	result := database.DB.Where("organization_id = ? AND category_id = ? AND id = ?", organizationID, categoryID, id).Delete(&models.AttributeDefinition{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAttributeNotFound
+	}
+
+	return nil
+}
+
+//checkMovedCategoryAttributes returns an error if the moved category defines an attribute of its new ancestors
+func checkMovedCategoryAttributes(tx *gorm.DB, organizationID string, id string, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+
+	var categories []models.Category = getCategories(tx, organizationID)
+
+	//get the attribute names of the category and its descendants
+	var subtreeIDs []string = append([]string{id}, getCategoryDescendantIDs(categories, id)...)
+
+	var names []string
+	for _, definition := range getAttributeDefinitions(tx, organizationID, subtreeIDs) {
+		names = append(names, definition.Name)
+	}
+
+	return checkAttributeNames(tx, organizationID, getCategoryAncestorIDs(categories, *parentID), names, "")
+}
+
+//normalizeTags returns the lowercase tags without duplicates
+func normalizeTags(tags []string) []string {
+	var normalized []string = []string{}
+	var seen map[string]bool = map[string]bool{}
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+//normalizeAttributes returns the attributes without the empty values
+func normalizeAttributes(attributes map[string]any) map[string]any {
+	var normalized map[string]any = map[string]any{}
+
+	for name, value := range attributes {
+		if value != nil {
+			normalized[name] = value
+		}
+	}
+
+	return normalized
+}
@@ -1,70 +1,119 @@
-package services
-
-import (
-	"errors"
-
-	"inventory-project-testing/database"
-	"inventory-project-testing/models"
-	"inventory-project-testing/utils"
-
-	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
-)
-
-//Signup return JWT token for the user
-func Signup(userInput models.UserRequest) (string, error){
-	//create a password using bcrypt Library
-	password, err := bcrypt.GenerateFromPassword([]byte(userInput.Password),bcrypt.DefaultCost)
-
-	//if password creation failed, return the errror
-	if err != nil {
-		return "",err
-	}
-
-	//create a new user object
-	//this user will be added into the database
-	var user models.User = models.User{
-		ID: uuid.New().String(),
-		Email: userInput.Email,
-		Password: string(password),
-	} 
-
-	//create a user into the database
-	database.DB.Create(&user)
-
-	//generate the JWT token 
-	token, err := utils.GeneralNewAccessToken()
-
-	//if generation is failed, return the error
-	if err != nil {
-		return "", err
-	}
-
-	//return the JWT token
-	return token, nil
-}
-
-
-func Login(userInput models.UserRequest) (string, error){
-	//create a variable called "user"
-	var user models.User
-
-	//find the user based on them email
-	result := database.DB.First(&user, "email = ?", userInput.Email)
-
-	//if the user is not found, return the error
-	if result.RowsAffected == 0 {
-		return "",errors.New("Invalid password")
-	}
-
-	//generate the JWT token 
-	token, err := utils.GeneralNewAccessToken()
-
-	//if gneration is failed, return the error 
-	if err != nil {
-		return "", err
-	}
-
-	//return the JWT token
-	return token, nil
-}
+package services
+
+import (
+	"errors"
+	"log"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+//ErrInvalidCredentials is returned when the email or the password is wrong
+var ErrInvalidCredentials = errors.New("Invalid password")
+
+//Signup return JWT token pair for the user
+//if the open signup is disabled, the users must be invited
+func Signup(userInput models.UserRequest) (models.TokenPair, error){
+	//if the open signup is disabled, return the error
+	if !IsOpenSignupAllowed() {
+		return models.TokenPair{}, ErrSignupDisabled
+	}
+
+	//create a password using bcrypt Library
+	password, err := bcrypt.GenerateFromPassword([]byte(userInput.Password),bcrypt.DefaultCost)
+
+	//if password creation failed, return the errror
+	if err != nil {
+		return models.TokenPair{},err
+	}
+
+	//create a new user object
+	//this user will be added into the database
+	var user models.User = models.User{
+		ID: uuid.New().String(),
+		Email: userInput.Email,
+		Password: string(password),
+		Role: models.RoleViewer,
+	} 
+
+	//create a user into the database
+	if err := database.DB.Create(&user).Error; err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//send the verification email to the user
+	//the user can request a new email if the delivery is failed
+	if err := SendVerificationEmail(user); err != nil {
+		log.Println("error when sending verification email:", err)
+	}
+
+	//generate the JWT token pair with a new token family
+	//the new user is not a member of any organization yet
+	return issueTokenPair(database.DB, user, "", uuid.New().String())
+}
+
+
+//Login return JWT token pair for the user
+//the failed attempts are counted for the account and the IP address
+//if the TOTP is enabled, the MFA token is returned instead of the token pair
+func Login(userInput models.UserRequest, ipAddress string) (models.LoginResult, error){
+	//if the login is temporarily blocked, return the error
+	if err := checkLoginThrottle(userInput.Email, ipAddress); err != nil {
+		return models.LoginResult{}, err
+	}
+
+	//create a variable called "user"
+	var user models.User
+
+	//find the user based on them email
+	result := database.DB.First(&user, "email = ?", userInput.Email)
+
+	//if the user is not found, return the error
+	if result.RowsAffected == 0 {
+		recordLoginFailure(userInput.Email, ipAddress, "")
+		return models.LoginResult{},ErrInvalidCredentials
+	}
+
+	//if the password is not matched, return the error
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(userInput.Password)); err != nil {
+		recordLoginFailure(userInput.Email, ipAddress, user.ID)
+		return models.LoginResult{},ErrInvalidCredentials
+	}
+
+	//if the account is disabled, return the error
+	if user.DisabledAt != nil {
+		return models.LoginResult{}, ErrAccountDisabled
+	}
+
+	//if the TOTP is enabled, the second factor is required
+	if user.TOTPEnabledAt != nil {
+		if err := ensureSigningKeys(); err != nil {
+			return models.LoginResult{}, err
+		}
+
+		mfaToken, err := utils.GenerateMFAToken(user)
+		if err != nil {
+			return models.LoginResult{}, err
+		}
+
+		return models.LoginResult{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}, nil
+	}
+
+	//the failed attempts of the account are reset after a successful login
+	resetLoginThrottle(user.Email)
+
+	//generate the JWT token pair with a new token family
+	tokenPair, err := issueTokenPair(database.DB, user, getDefaultOrganizationID(user.ID), uuid.New().String())
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+
+	return models.LoginResult{TokenPair: &tokenPair}, nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"inventory-project-testing/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//ErrItemNotFound is returned when the item is not found in the organization
+var ErrItemNotFound = errors.New("item not found")
+
+//ErrDuplicateSKU is returned when the SKU is used by another item of the organization
+var ErrDuplicateSKU = errors.New("sku is already used by another item")
+
+//ErrDuplicateBarcode is returned when the barcode is used by another item of the organization
+var ErrDuplicateBarcode = errors.New("barcode is already used by another item")
+
+//checkItemIdentifiers returns an error if the SKU or the barcodes are used by another item of the organization
+func checkItemIdentifiers(tx *gorm.DB, organizationID string, itemID string, sku string, codes []string) error {
+	var count int64
+
+	if err := tx.Model(&models.Item{}).Where("organization_id = ? AND sku = ? AND id <> ?", organizationID, sku, itemID).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrDuplicateSKU
+	}
+
+	if len(codes) == 0 {
+		return nil
+	}
+
+	//the UPC-A and the EAN-13 form of the code are the same barcode
+	var lookupCodes []string
+	for _, code := range codes {
+		lookupCodes = append(lookupCodes, getBarcodeLookupCodes(code)...)
+	}
+
+	if err := tx.Model(&models.ItemBarcode{}).Where("organization_id = ? AND code IN ? AND item_id <> ?", organizationID, lookupCodes, itemID).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrDuplicateBarcode
+	}
+
+	return nil
+}
+
+//newItemBarcodes returns the barcodes of the item with their symbology
+//the UPC-A codes are stored in the EAN-13 form, the same barcode is only stored once
+func newItemBarcodes(organizationID string, itemID string, codes []string) []models.ItemBarcode {
+	var barcodes []models.ItemBarcode = []models.ItemBarcode{}
+	var isAdded map[string]bool = map[string]bool{}
+
+	for _, code := range codes {
+		code = models.NormalizeBarcode(code)
+
+		if isAdded[code] {
+			continue
+		}
+
+		isAdded[code] = true
+		symbology, _ := models.GetBarcodeSymbology(code)
+
+		barcodes = append(barcodes, models.ItemBarcode{
+			ID:             uuid.New().String(),
+			OrganizationID: organizationID,
+			ItemID:         itemID,
+			Code:           code,
+			Symbology:      symbology,
+		})
+	}
+
+	return barcodes
+}
+
+//createItemBarcodes inserts the barcodes of the item
+//the barcode that is inserted by another request after the check is rejected by the unique index
+func createItemBarcodes(tx *gorm.DB, barcodes []models.ItemBarcode) error {
+	if len(barcodes) == 0 {
+		return nil
+	}
+
+	err := tx.Create(&barcodes).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrDuplicateBarcode
+	}
+
+	return err
+}
+
+//getItemSaveError returns ErrDuplicateSKU if the SKU is inserted by another request after the check
+func getItemSaveError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrDuplicateSKU
+	}
+
+	return err
+}
+
+//getBarcodeLookupCodes returns the codes that are checked for the scanned code
+//the code is normalized into the EAN-13 form, the UPC-A form is checked for the barcodes stored before the normalization
+func getBarcodeLookupCodes(code string) []string {
+	code = models.NormalizeBarcode(code)
+	var codes []string = []string{code}
+
+	if symbology, isValid := models.GetBarcodeSymbology(code); isValid && symbology == models.SymbologyEAN13 && strings.HasPrefix(code, "0") {
+		codes = append(codes, code[1:])
+	}
+
+	return codes
+}
+
+//GetItemByBarcode returns the item of the organization with the scanned barcode
+//the SKU of the item is checked if no barcode is matched
+func GetItemByBarcode(organizationID string, code string) (models.Item, error) {
+	code = strings.TrimSpace(code)
+
+	//find the barcode in the organization
+	var barcode models.ItemBarcode
+	result := tenantDB(organizationID).Limit(1).Find(&barcode, "code IN ?", getBarcodeLookupCodes(code))
+
+	if result.RowsAffected > 0 {
+		return GetItemByID(organizationID, barcode.ItemID)
+	}
+
+	//find the item with the SKU
+	var item models.Item
+	result = tenantDB(organizationID).Limit(1).Find(&item, "sku = ?", code)
+
+	if result.RowsAffected == 0 {
+		return models.Item{}, ErrItemNotFound
+	}
+
+	return GetItemByID(organizationID, item.ID)
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrCategoryNotFound is returned when the category is not found in the organization
+var ErrCategoryNotFound = errors.New("category not found")
+
+//ErrCategoryCycle is returned when the category is moved under itself or its descendants
+var ErrCategoryCycle = errors.New("category cannot be moved under itself or its descendants")
+
+//ErrCategoryNotEmpty is returned when the category that has children or items is deleted
+var ErrCategoryNotEmpty = errors.New("category still has subcategories or items")
+
+//ErrDuplicateCategory is returned when the parent already has a category with the same name
+var ErrDuplicateCategory = errors.New("category with the same name already exists under the parent")
+
+//getCategories returns every category of the organization
+func getCategories(tx *gorm.DB, organizationID string) []models.Category {
+	var categories []models.Category = []models.Category{}
+	tx.Where("organization_id = ?", organizationID).Order("name asc").Find(&categories)
+	return categories
+}
+
+//getCategoryTreeIDs returns the ID of the category with the IDs of all its descendants
+func getCategoryTreeIDs(organizationID string, id string) ([]string, error) {
+	var categories []models.Category = getCategories(database.DB, organizationID)
+
+	//if the category is not found, return the error
+	if len(getCategoryAncestorIDs(categories, id)) == 0 {
+		return nil, ErrCategoryNotFound
+	}
+
+	return append([]string{id}, getCategoryDescendantIDs(categories, id)...), nil
+}
+
+//getCategoryAncestorIDs returns the ID of the category with the IDs of all its ancestors
+func getCategoryAncestorIDs(categories []models.Category, id string) []string {
+	var parents map[string]*string = map[string]*string{}
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+
+	//walk up the tree from the category
+	//the number of steps is limited in case the stored tree is already broken
+	var ids []string
+	var current *string = &id
+	for steps := 0; current != nil && steps <= len(categories); steps++ {
+		if _, ok := parents[*current]; !ok {
+			break
+		}
+
+		ids = append(ids, *current)
+		current = parents[*current]
+	}
+
+	return ids
+}
+
+//getCategoryDescendantIDs returns the IDs of the descendants of the category
+func getCategoryDescendantIDs(categories []models.Category, id string) []string {
+	var children map[string][]string = map[string][]string{}
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+
+	//walk down the tree from the category
+	var ids []string = []string{}
+	var queue []string = []string{id}
+	for len(queue) > 0 {
+		ids = append(ids, children[queue[0]]...)
+		queue = append(queue[1:], children[queue[0]]...)
+	}
+
+	return ids
+}
+
+//isDescendantCategory returns true if the parent is the category itself or one of its descendants
+func isDescendantCategory(categories []models.Category, parentID string, id string) bool {
+	for _, ancestorID := range getCategoryAncestorIDs(categories, parentID) {
+		if ancestorID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+//checkCategoryParent returns an error if the parent is not found or the name is used under the parent
+func checkCategoryParent(tx *gorm.DB, organizationID string, id string, categoryInput models.CategoryRequest) error {
+	var query *gorm.DB = tx.Model(&models.Category{}).Where("organization_id = ? AND name = ? AND id <> ?", organizationID, categoryInput.Name, id)
+
+	if categoryInput.ParentID != nil {
+		var parent models.Category
+		if tx.Limit(1).Find(&parent, "organization_id = ? AND id = ?", organizationID, *categoryInput.ParentID).RowsAffected == 0 {
+			return ErrCategoryNotFound
+		}
+
+		query = query.Where("parent_id = ?", *categoryInput.ParentID)
+	} else {
+		query = query.Where("parent_id IS NULL")
+	}
+
+	var count int64
+	query.Count(&count)
+	if count > 0 {
+		return ErrDuplicateCategory
+	}
+
+	return nil
+}
+
+//GetCategoryTree returns the root categories of the organization with their descendants
+func GetCategoryTree(organizationID string) []models.Category {
+	var categories []models.Category = getCategories(database.DB, organizationID)
+
+	//group the categories by their parent
+	var children map[string][]models.Category = map[string][]models.Category{}
+	var roots []models.Category = []models.Category{}
+
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	//attach the children to their parent recursively
+	var attach func(categories []models.Category)
+	attach = func(categories []models.Category) {
+		for i := range categories {
+			categories[i].Children = children[categories[i].ID]
+			attach(categories[i].Children)
+		}
+	}
+
+	attach(roots)
+
+	return roots
+}
+
+//GetCategoryByID returns the category of the organization with its direct children
+func GetCategoryByID(organizationID string, id string) (models.Category, error) {
+	var category models.Category
+
+	if tenantDB(organizationID).Limit(1).Find(&category, "id = ?", id).RowsAffected == 0 {
+		return models.Category{}, ErrCategoryNotFound
+	}
+
+	category.Children = []models.Category{}
+	tenantDB(organizationID).Where("parent_id = ?", id).Order("name asc").Find(&category.Children)
+
+	return category, nil
+}
+
+//CreateCategory creates a new category in the organization
+func CreateCategory(organizationID string, categoryInput models.CategoryRequest) (models.Category, error) {
+	var category models.Category = models.Category{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		ParentID:       categoryInput.ParentID,
+		Name:           categoryInput.Name,
+		Description:    categoryInput.Description,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		//if the parent is not found or the name is already used, return the error
+		if err := checkCategoryParent(tx, organizationID, category.ID, categoryInput); err != nil {
+			return err
+		}
+
+		return tx.Create(&category).Error
+	})
+
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+//UpdateCategory updates the category of the organization
+//the category can be moved under another parent except its own descendants
+func UpdateCategory(organizationID string, id string, categoryInput models.CategoryRequest) (models.Category, error) {
+	var category models.Category
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		if tx.Limit(1).Find(&category, "organization_id = ? AND id = ?", organizationID, id).RowsAffected == 0 {
+			return ErrCategoryNotFound
+		}
+
+		//if the new parent is the category or its descendant, return the error
+		if categoryInput.ParentID != nil && isDescendantCategory(getCategories(tx, organizationID), *categoryInput.ParentID, id) {
+			return ErrCategoryCycle
+		}
+
+		if err := checkCategoryParent(tx, organizationID, id, categoryInput); err != nil {
+			return err
+		}
+
+		//if the new ancestors define the same attributes, return the error
+		if err := checkMovedCategoryAttributes(tx, organizationID, id, categoryInput.ParentID); err != nil {
+			return err
+		}
+
+		category.ParentID = categoryInput.ParentID
+		category.Name = categoryInput.Name
+		category.Description = categoryInput.Description
+		category.UpdatedAt = time.Now()
+
+		return tx.Save(&category).Error
+	})
+
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+//DeleteCategory deletes the category of the organization
+//the category cannot be deleted if it still has subcategories or items
+func DeleteCategory(organizationID string, id string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		//lock the category so no item can be assigned to it until it is deleted
+		var category models.Category
+		if tx.Clauses(clause.Locking{Strength: "UPDATE"}).Limit(1).Find(&category, "organization_id = ? AND id = ?", organizationID, id).RowsAffected == 0 {
+			return ErrCategoryNotFound
+		}
+
+		//if the category has subcategories or items, return the error
+		var children, items int64
+		tx.Model(&models.Category{}).Where("parent_id = ?", id).Count(&children)
+		tx.Model(&models.Item{}).Where("category_id = ?", id).Count(&items)
+
+		if children > 0 || items > 0 {
+			return ErrCategoryNotEmpty
+		}
+
+		//delete the attributes of the category with the category
+		if err := tx.Where("category_id = ?", id).Delete(&models.AttributeDefinition{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&category).Error
+	})
+}
+
+//checkItemCategory returns an error if the category of the item is not found in the organization
+//the category is locked until the item is saved so it cannot be deleted at the same time
+func checkItemCategory(tx *gorm.DB, organizationID string, categoryID *string) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	var category models.Category
+	if tx.Clauses(clause.Locking{Strength: "SHARE"}).Limit(1).Find(&category, "organization_id = ? AND id = ?", organizationID, *categoryID).RowsAffected == 0 {
+		return ErrCategoryNotFound
+	}
+
+	return nil
+}
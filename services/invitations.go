@@ -0,0 +1,230 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrInvalidInvitation is returned when the invitation cannot be accepted
+var ErrInvalidInvitation = errors.New("invitation is invalid or expired")
+
+//ErrSignupDisabled is returned when the open signup is disabled
+var ErrSignupDisabled = errors.New("signup is disabled, ask an admin for an invitation")
+
+//IsOpenSignupAllowed returns true if anyone can sign up without an invitation
+//the policy is configured with ALLOW_OPEN_SIGNUP in the .env file
+func IsOpenSignupAllowed() bool {
+	allowed, err := strconv.ParseBool(utils.GetValue("ALLOW_OPEN_SIGNUP"))
+
+	//the open signup is allowed if the policy is not configured
+	if err != nil {
+		return true
+	}
+
+	return allowed
+}
+
+//CreateInvitation invites the email into the organization
+//the invitation link is sent to the email
+func CreateInvitation(organizationID string, invitedBy models.User, invitationInput models.InvitationRequest) (models.Invitation, error) {
+	//if the email is already a member of the organization, return an error
+	var count int64
+	database.DB.Model(&models.Membership{}).
+		Joins("JOIN users ON users.id = memberships.user_id").
+		Where("memberships.organization_id = ? AND users.email = ?", organizationID, invitationInput.Email).
+		Count(&count)
+
+	if count > 0 {
+		return models.Invitation{}, errors.New("the email is already a member of the organization")
+	}
+
+	//make sure the signing keys are loaded
+	if err := ensureSigningKeys(); err != nil {
+		return models.Invitation{}, err
+	}
+
+	//get the invitation expire time from .env file
+	hoursCount, _ := strconv.Atoi(utils.GetValue("INVITATION_EXPIRE_HOURS_COUNT"))
+
+	var now time.Time = time.Now()
+
+	//create a new invitation object
+	var invitation models.Invitation = models.Invitation{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		Email:          invitationInput.Email,
+		Role:           invitationInput.Role,
+		InvitedBy:      invitedBy.ID,
+		ExpiresAt:      now.Add(time.Hour * time.Duration(hoursCount)),
+		CreatedAt:      now,
+	}
+
+	//create the signed invitation token
+	token, err := utils.GenerateInvitationToken(invitation)
+	if err != nil {
+		return models.Invitation{}, err
+	}
+
+	//insert the invitation into the database
+	if err := database.DB.Create(&invitation).Error; err != nil {
+		return models.Invitation{}, err
+	}
+
+	//send the invitation link to the email
+	err = getMailer().Send(utils.Mail{
+		To:      invitation.Email,
+		Subject: "You are invited to join an organization",
+		Body: "You are invited to join the organization as " + invitation.Role + ". The invitation expires in " + strconv.Itoa(hoursCount) + " hours.\n\n" +
+			utils.GetValue("APP_URL") + "/accept-invite?token=" + token,
+	})
+
+	if err != nil {
+		return models.Invitation{}, err
+	}
+
+	return invitation, nil
+}
+
+//GetInvitations returns the invitations of the organization
+func GetInvitations(organizationID string) []models.Invitation {
+	// create a variable to store invitations data
+	var invitations []models.Invitation = []models.Invitation{}
+
+	// get all invitations of the organization order by created_at
+	tenantDB(organizationID).Order("created_at desc").Find(&invitations)
+
+	// return the invitations
+	return invitations
+}
+
+//RevokeInvitation revokes the pending invitation of the organization
+func RevokeInvitation(organizationID string, id string) error {
+	//revoke the invitation if it is still pending
+	result := tenantDB(organizationID).Model(&models.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	//if the invitation is not found, return an error
+	if result.RowsAffected == 0 {
+		return errors.New("invitation not found")
+	}
+
+	return nil
+}
+
+//AcceptInvitation adds the user into the organization of the invitation
+//a new user is created if the email does not have an account yet
+//the token pair for the organization is only returned for the new user,
+//the existing user must log in because the invitation does not prove the password or the second factor
+func AcceptInvitation(acceptInput models.AcceptInvitationRequest) (*models.TokenPair, error) {
+	//make sure the signing keys are loaded
+	if err := ensureSigningKeys(); err != nil {
+		return nil, err
+	}
+
+	//get the invitation ID from the signed token
+	invitationID, err := utils.ParseInvitationToken(acceptInput.Token)
+	if err != nil {
+		return nil, ErrInvalidInvitation
+	}
+
+	var tokenPair *models.TokenPair
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		//find the invitation and lock it until the transaction is finished
+		var invitation models.Invitation
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invitation, "id = ?", invitationID)
+
+		//the invitation can only be accepted once before it expires
+		if result.RowsAffected == 0 || invitation.AcceptedAt != nil || invitation.RevokedAt != nil || time.Now().After(invitation.ExpiresAt) {
+			return ErrInvalidInvitation
+		}
+
+		var now time.Time = time.Now()
+
+		//find the user with the invited email
+		var user models.User
+		var isNewUser bool = tx.Limit(1).Find(&user, "email = ?", invitation.Email).RowsAffected == 0
+
+		if isNewUser {
+			//the password is required to create a new user
+			if acceptInput.Password == "" {
+				return errors.New("password is required to create a new account")
+			}
+
+			password, err := bcrypt.GenerateFromPassword([]byte(acceptInput.Password), bcrypt.DefaultCost)
+			if err != nil {
+				return err
+			}
+
+			//the email is verified because the invitation is sent to the email
+			user = models.User{
+				ID:              uuid.New().String(),
+				Email:           invitation.Email,
+				Password:        string(password),
+				Role:            models.RoleViewer,
+				EmailVerifiedAt: &now,
+			}
+
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		}
+
+		//if the account is disabled, return the error
+		if user.DisabledAt != nil {
+			return ErrAccountDisabled
+		}
+
+		//add the user into the organization with the invited role
+		var membership models.Membership = models.Membership{
+			ID:             uuid.New().String(),
+			OrganizationID: invitation.OrganizationID,
+			UserID:         user.ID,
+			Role:           invitation.Role,
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"})}).Create(&membership).Error; err != nil {
+			return err
+		}
+
+		//mark the invitation as accepted
+		if err := tx.Model(&invitation).Update("accepted_at", now).Error; err != nil {
+			return err
+		}
+
+		//the existing user joins the organization after the normal login
+		if !isNewUser {
+			return nil
+		}
+
+		//generate the JWT token pair for the organization with a new token family
+		newTokenPair, err := issueTokenPair(tx, user, invitation.OrganizationID, uuid.New().String())
+		if err != nil {
+			return err
+		}
+
+		tokenPair = &newTokenPair
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenPair, nil
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"inventory-project-testing/models"
+
+	"gorm.io/gorm"
+)
+
+//defaultItemLimit is the number of items returned if the limit is not provided
+const defaultItemLimit = 20
+
+//ErrInvalidCursor is returned when the cursor cannot be decoded
+var ErrInvalidCursor = errors.New("cursor is invalid")
+
+//itemCursor points to the last item of the previous page
+//the values of the sort fields are stored together with the ID of the item
+type itemCursor struct {
+	Values []any  `json:"v"`
+	ID     string `json:"id"`
+}
+
+//filterItems adds the filters of the query into the item query
+func filterItems(query *gorm.DB, itemQuery models.ItemQuery) *gorm.DB {
+	// filter the items by name
+	if itemQuery.Name != "" {
+		query = query.Where("name LIKE ? ESCAPE '\\\\'", "%"+escapeLike(itemQuery.Name)+"%")
+	}
+
+	// filter the items by price range
+	if itemQuery.MinPrice != nil {
+		query = query.Where("price >= ?", *itemQuery.MinPrice)
+	}
+
+	if itemQuery.MaxPrice != nil {
+		query = query.Where("price <= ?", *itemQuery.MaxPrice)
+	}
+
+	// filter the items by quantity range
+	if itemQuery.MinQuantity != nil {
+		query = query.Where("quantity >= ?", *itemQuery.MinQuantity)
+	}
+
+	if itemQuery.MaxQuantity != nil {
+		query = query.Where("quantity <= ?", *itemQuery.MaxQuantity)
+	}
+
+	// filter the variants of the product
+	if itemQuery.ParentID != "" {
+		query = query.Where("parent_id = ?", itemQuery.ParentID)
+	}
+
+	// filter the items that are stocked in the location or the warehouse
+	if itemQuery.LocationID != "" {
+		query = query.Where("id IN (SELECT item_id FROM stock_levels WHERE location_id = ? AND quantity > 0)", itemQuery.LocationID)
+	}
+
+	if itemQuery.WarehouseID != "" {
+		query = query.Where("id IN (SELECT stock_levels.item_id FROM stock_levels JOIN locations ON locations.id = stock_levels.location_id "+
+			"WHERE locations.warehouse_id = ? AND stock_levels.quantity > 0)", itemQuery.WarehouseID)
+	}
+
+	// filter the items that have every tag
+	for _, tag := range itemQuery.GetTags() {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", tag)
+	}
+
+	// filter the items by the values of the attributes
+	for _, filter := range itemQuery.AttributeFilters {
+		query = filterAttribute(query, filter)
+	}
+
+	// filter the items by created and updated date ranges
+	query = filterDateRange(query, "created_at", itemQuery.CreatedFrom, itemQuery.CreatedTo)
+	query = filterDateRange(query, "updated_at", itemQuery.UpdatedFrom, itemQuery.UpdatedTo)
+
+	return query
+}
+
+//filterAttribute adds the attribute filter into the query
+//the numbers are compared as numbers, the other values are compared as text
+func filterAttribute(query *gorm.DB, filter models.AttributeFilter) *gorm.DB {
+	// the name of the attribute is validated so it can be used inside the JSON path
+	var path string = `$."` + filter.Name + `"`
+	var value string = "JSON_UNQUOTE(JSON_EXTRACT(attributes, ?))"
+
+	if filter.IsNumber() {
+		value = "CAST(JSON_EXTRACT(attributes, ?) AS DECIMAL(65,10))"
+	}
+
+	switch filter.Operator {
+	case models.AttributeFilterGreaterThanEqual:
+		return query.Where(value+" >= ?", path, filter.Value)
+	case models.AttributeFilterLessThanEqual:
+		return query.Where(value+" <= ?", path, filter.Value)
+	default:
+		return query.Where(value+" = ?", path, filter.Value)
+	}
+}
+
+//filterDateRange adds the date range of the column into the query
+//the whole day of the end date is included
+func filterDateRange(query *gorm.DB, column string, from string, to string) *gorm.DB {
+	if date, err := time.ParseInLocation("2006-01-02", from, time.Local); err == nil {
+		query = query.Where(column+" >= ?", date)
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", to, time.Local); err == nil {
+		query = query.Where(column+" < ?", date.AddDate(0, 0, 1))
+	}
+
+	return query
+}
+
+//sortItems adds the sort fields into the item query
+//the ID of the item is used as the last sort field to keep the order stable
+func sortItems(query *gorm.DB, sorts []models.ItemSort) *gorm.DB {
+	for _, sort := range sorts {
+		if sort.Descending {
+			query = query.Order(sort.Field + " desc")
+		} else {
+			query = query.Order(sort.Field + " asc")
+		}
+	}
+
+	return query.Order("id asc")
+}
+
+//seekItems returns the items after the cursor in the order of the sort fields
+func seekItems(query *gorm.DB, sorts []models.ItemSort, cursor itemCursor) (*gorm.DB, error) {
+	if len(cursor.Values) != len(sorts) {
+		return nil, ErrInvalidCursor
+	}
+
+	// convert the values of the cursor into the types of the sort fields
+	var values []any = make([]any, len(sorts))
+	for i, sort := range sorts {
+		value, err := parseCursorValue(sort.Field, cursor.Values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	// the items after the cursor are matched with the conditions below
+	// (a > x) OR (a = x AND b > y) OR (a = x AND b = y AND id > z)
+	var conditions []string
+	var args []any
+
+	for i := 0; i <= len(sorts); i++ {
+		var parts []string
+
+		// the previous sort fields are equal to the cursor
+		for j := 0; j < i; j++ {
+			parts = append(parts, sorts[j].Field+" = ?")
+			args = append(args, values[j])
+		}
+
+		// the current sort field is after the cursor
+		if i < len(sorts) {
+			var operator string = " > ?"
+			if sorts[i].Descending {
+				operator = " < ?"
+			}
+
+			parts = append(parts, sorts[i].Field+operator)
+			args = append(args, values[i])
+		} else {
+			parts = append(parts, "id > ?")
+			args = append(args, cursor.ID)
+		}
+
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...), nil
+}
+
+//encodeItemCursor returns the cursor that points to the item
+func encodeItemCursor(item models.Item, sorts []models.ItemSort) string {
+	var cursor itemCursor = itemCursor{ID: item.ID}
+
+	for _, sort := range sorts {
+		switch sort.Field {
+		case "name":
+			cursor.Values = append(cursor.Values, item.Name)
+		case "price":
+			cursor.Values = append(cursor.Values, item.Price)
+		case "quantity":
+			cursor.Values = append(cursor.Values, item.Quantity)
+		case "created_at":
+			cursor.Values = append(cursor.Values, item.CreatedAt.Format(time.RFC3339Nano))
+		case "updated_at":
+			cursor.Values = append(cursor.Values, item.UpdatedAt.Format(time.RFC3339Nano))
+		}
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+//decodeItemCursor returns the cursor from its encoded value
+func decodeItemCursor(encoded string) (itemCursor, error) {
+	var cursor itemCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return itemCursor{}, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return itemCursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+//parseCursorValue converts the value of the cursor into the type of the sort field
+func parseCursorValue(field string, value any) (any, error) {
+	switch field {
+	case "name":
+		if name, ok := value.(string); ok {
+			return name, nil
+		}
+	case "price", "quantity":
+		if number, ok := value.(float64); ok {
+			return int(number), nil
+		}
+	case "created_at", "updated_at":
+		if text, ok := value.(string); ok {
+			if date, err := time.Parse(time.RFC3339Nano, text); err == nil {
+				return date, nil
+			}
+		}
+	}
+
+	return nil, ErrInvalidCursor
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+//keyLoadMutex prevents concurrent loading of the signing keys
+var keyLoadMutex sync.Mutex
+
+//keyReloadInterval is the minimum time between the reloads that are caused by the unknown keys
+const keyReloadInterval = time.Second * 10
+
+//keyReloadMutex protects the time of the last reload
+var keyReloadMutex sync.Mutex
+
+//lastKeyReloadAt is the time of the last reload that was caused by an unknown key
+var lastKeyReloadAt time.Time
+
+//LoadSigningKeys loads the signing keys from the database into the keyring
+//a new signing key is created when there is no active key
+func LoadSigningKeys() error {
+	keyLoadMutex.Lock()
+	defer keyLoadMutex.Unlock()
+
+	var now time.Time = time.Now()
+
+	//get every key that can still verify the tokens
+	//the newest key is placed first
+	var signingKeys []models.SigningKey
+	if err := database.DB.Where("expires_at > ?", now).Order("created_at desc").Find(&signingKeys).Error; err != nil {
+		return err
+	}
+
+	//if there is no active key, rotate the signing key
+	if len(signingKeys) == 0 || !signingKeys[0].ActiveUntil.After(now) {
+		newKey, err := createSigningKey(now)
+		if err != nil {
+			return err
+		}
+
+		signingKeys = append([]models.SigningKey{newKey}, signingKeys...)
+	}
+
+	//parse every key from the database
+	var keys []utils.Key
+	for _, signingKey := range signingKeys {
+		key, err := utils.ParseSigningKey(signingKey)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+	}
+
+	//the newest key signs the new tokens
+	utils.SetSigningKeys(keys[0], keys)
+	return nil
+}
+
+//createSigningKey inserts a new signing key into the database
+func createSigningKey(now time.Time) (models.SigningKey, error) {
+	//get the signing configuration from .env file
+	var algorithm string = utils.GetValue("JWT_SIGNING_ALGORITHM")
+	rotationHours, _ := strconv.Atoi(utils.GetValue("JWT_KEY_ROTATION_HOURS"))
+	graceHours, _ := strconv.Atoi(utils.GetValue("JWT_KEY_GRACE_HOURS"))
+
+	//make sure the signing key is rotated
+	if rotationHours <= 0 {
+		return models.SigningKey{}, errors.New("JWT_KEY_ROTATION_HOURS must be greater than 0")
+	}
+
+	//generate a new key pair
+	privateKey, publicKey, err := utils.GenerateSigningKeyPEM(algorithm)
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	//the key keeps verifying the tokens during the grace period
+	var activeUntil time.Time = now.Add(time.Hour * time.Duration(rotationHours))
+
+	var signingKey models.SigningKey = models.SigningKey{
+		ID:          uuid.New().String(),
+		Algorithm:   algorithm,
+		PrivateKey:  privateKey,
+		PublicKey:   publicKey,
+		ActiveUntil: activeUntil,
+		ExpiresAt:   activeUntil.Add(time.Hour * time.Duration(graceHours)),
+	}
+
+	//insert the signing key into the database
+	if err := database.DB.Create(&signingKey).Error; err != nil {
+		return models.SigningKey{}, err
+	}
+
+	log.Println("Signing key", signingKey.ID, "is created")
+	return signingKey, nil
+}
+
+//RotateSigningKeys reloads the signing keys and removes the expired keys
+//this function is used by the scheduled key rotation
+func RotateSigningKeys() {
+	//remove the keys that cannot verify the tokens anymore
+	if err := database.DB.Where("expires_at <= ?", time.Now()).Delete(&models.SigningKey{}).Error; err != nil {
+		log.Println("error when removing expired signing keys:", err)
+	}
+
+	//load the keys, a new key is created when the active key is expired
+	if err := LoadSigningKeys(); err != nil {
+		log.Println("error when rotating signing keys:", err)
+	}
+}
+
+//JWTKeyFunc returns the public key that verifies the token
+//the keys are reloaded when the token is signed by an unknown key,
+//for example a key that was created by another instance of the application
+func JWTKeyFunc(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+
+	if !utils.HasVerificationKey(keyID) {
+		if err := reloadSigningKeys(); err != nil {
+			return nil, err
+		}
+	}
+
+	return utils.JWTKeyFunc(token)
+}
+
+//reloadSigningKeys loads the signing keys at most once in the reload interval
+//the tokens with unknown keys cannot turn every request into a database query
+func reloadSigningKeys() error {
+	keyReloadMutex.Lock()
+
+	if time.Since(lastKeyReloadAt) < keyReloadInterval {
+		keyReloadMutex.Unlock()
+		return nil
+	}
+
+	lastKeyReloadAt = time.Now()
+	keyReloadMutex.Unlock()
+
+	return LoadSigningKeys()
+}
+
+//ensureSigningKeys loads the signing keys if the keyring is empty
+func ensureSigningKeys() error {
+	if utils.HasSigningKey() {
+		return nil
+	}
+
+	return LoadSigningKeys()
+}
+
+//GetJWKS returns the public keys that verify the JWT tokens
+func GetJWKS() (models.JSONWebKeySet, error) {
+	//make sure the signing keys are loaded
+	if err := ensureSigningKeys(); err != nil {
+		return models.JSONWebKeySet{}, err
+	}
+
+	return utils.GetJWKS(), nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"strconv"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+)
+
+//getItemLabel returns the content of the label of the item
+//the QR code links to the item in the application
+func getItemLabel(item models.Item) utils.Label {
+	return utils.Label{
+		Name:  item.Name,
+		Price: "Price " + strconv.Itoa(item.Price),
+		SKU:   item.SKU,
+		URL:   utils.GetValue("APP_URL") + "/items/" + item.ID,
+	}
+}
+
+//GetItemLabel returns the label of the item in the PNG or PDF format
+func GetItemLabel(organizationID string, id string, format string) ([]byte, error) {
+	item, err := GetItemByID(organizationID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == models.LabelFormatPDF {
+		return utils.RenderLabelPDF([]utils.Label{getItemLabel(item)})
+	}
+
+	return utils.RenderLabelPNG(getItemLabel(item))
+}
+
+//GetItemLabelSheet returns the printable PDF sheets with the labels of the items
+//the labels are printed in the order of the item IDs
+func GetItemLabelSheet(organizationID string, ids []string) ([]byte, error) {
+	//get the items of the organization
+	var items []models.Item
+	if err := tenantDB(organizationID).Where("id IN ?", ids).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	var itemsByID map[string]models.Item = map[string]models.Item{}
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	//if any item is not found, return the error
+	var labels []utils.Label
+	for _, id := range ids {
+		item, ok := itemsByID[id]
+		if !ok {
+			return nil, ErrItemNotFound
+		}
+
+		labels = append(labels, getItemLabel(item))
+	}
+
+	return utils.RenderLabelSheetPDF(labels)
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//LoginBlockedError is returned when the login is temporarily blocked
+type LoginBlockedError struct {
+	RetryAfter time.Duration
+}
+
+func (err LoginBlockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %d seconds", int(math.Ceil(err.RetryAfter.Seconds())))
+}
+
+//accountThrottleKey returns the throttle key for the email
+func accountThrottleKey(email string) string {
+	return "account:" + email
+}
+
+//ipThrottleKey returns the throttle key for the IP address
+func ipThrottleKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
+
+//getLockoutDuration returns the duration of the temporary lockout
+func getLockoutDuration() time.Duration {
+	minutesCount, _ := strconv.Atoi(utils.GetValue("LOGIN_LOCKOUT_MINUTES"))
+	return time.Minute * time.Duration(minutesCount)
+}
+
+//checkLoginThrottle returns an error if the login is blocked for one of the keys
+func checkLoginThrottle(email string, ipAddress string) error {
+	var now time.Time = time.Now()
+
+	//find the active blocks of the account and the IP address
+	var throttles []models.LoginThrottle
+	database.DB.Where("`key` IN ? AND blocked_until > ?", []string{accountThrottleKey(email), ipThrottleKey(ipAddress)}, now).Find(&throttles)
+
+	//if there is no active block, the login is allowed
+	if len(throttles) == 0 {
+		return nil
+	}
+
+	//wait until the longest block is over
+	var retryAfter time.Duration
+	for _, throttle := range throttles {
+		if wait := throttle.BlockedUntil.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	recordLoginEvent(models.LoginEvent{Type: models.LoginEventBlocked, Email: email, IPAddress: ipAddress})
+	return LoginBlockedError{RetryAfter: retryAfter}
+}
+
+//recordLoginFailure counts the failed login for the account and the IP address
+func recordLoginFailure(email string, ipAddress string, userID string) {
+	recordLoginEvent(models.LoginEvent{Type: models.LoginEventFailed, Email: email, IPAddress: ipAddress, UserID: userID})
+
+	//the account is blocked with exponential backoff
+	//and locked after the maximum number of failures
+	accountMaxFailures, _ := strconv.Atoi(utils.GetValue("LOGIN_MAX_FAILURES"))
+	if locked := incrementLoginThrottle(accountThrottleKey(email), accountMaxFailures, true); locked {
+		recordLoginEvent(models.LoginEvent{
+			Type:      models.LoginEventLockout,
+			Email:     email,
+			IPAddress: ipAddress,
+			UserID:    userID,
+			Details:   "account is locked after " + strconv.Itoa(accountMaxFailures) + " failed attempts",
+		})
+	}
+
+	//the IP address is locked after the maximum number of failures
+	ipMaxFailures, _ := strconv.Atoi(utils.GetValue("LOGIN_IP_MAX_FAILURES"))
+	if locked := incrementLoginThrottle(ipThrottleKey(ipAddress), ipMaxFailures, false); locked {
+		recordLoginEvent(models.LoginEvent{
+			Type:      models.LoginEventLockout,
+			Email:     email,
+			IPAddress: ipAddress,
+			Details:   "ip address is locked after " + strconv.Itoa(ipMaxFailures) + " failed attempts",
+		})
+	}
+}
+
+//incrementLoginThrottle increments the failures of the key
+//true is returned if the key is locked by this failure
+func incrementLoginThrottle(key string, maxFailures int, withBackoff bool) bool {
+	var isLocked bool
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var now time.Time = time.Now()
+		var lockoutDuration time.Duration = getLockoutDuration()
+
+		//insert the throttle or count the failure in one statement
+		//start counting again if the last failure is old enough
+		//the row stays locked until the transaction is finished, so concurrent failures are counted correctly
+		err := tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "failures"}, Value: gorm.Expr("IF(last_failure_at < ?, 1, failures + 1)", now.Add(-lockoutDuration))},
+				{Column: clause.Column{Name: "last_failure_at"}, Value: now},
+				{Column: clause.Column{Name: "updated_at"}, Value: now},
+			},
+		}).Create(&models.LoginThrottle{Key: key, Failures: 1, LastFailureAt: now}).Error
+
+		if err != nil {
+			return err
+		}
+
+		var throttle models.LoginThrottle
+		if err := tx.First(&throttle, "`key` = ?", key).Error; err != nil {
+			return err
+		}
+
+		var blockedUntil *time.Time
+
+		if maxFailures > 0 && throttle.Failures >= maxFailures {
+			//lock the key temporarily
+			var lockedUntil time.Time = now.Add(lockoutDuration)
+			blockedUntil = &lockedUntil
+			isLocked = throttle.Failures == maxFailures
+		} else if withBackoff {
+			//block the key with exponential backoff
+			var backoffUntil time.Time = now.Add(getBackoffDuration(throttle.Failures))
+			blockedUntil = &backoffUntil
+		}
+
+		//update the block of the throttle
+		return tx.Model(&models.LoginThrottle{}).Where("`key` = ?", key).Update("blocked_until", blockedUntil).Error
+	})
+
+	if err != nil {
+		log.Println("error when recording failed login:", err)
+	}
+
+	return isLocked
+}
+
+//getBackoffDuration returns the waiting time after the given number of failures
+//the waiting time is doubled after every failure
+func getBackoffDuration(failures int) time.Duration {
+	baseSeconds, _ := strconv.Atoi(utils.GetValue("LOGIN_BACKOFF_BASE_SECONDS"))
+	maxSeconds, _ := strconv.Atoi(utils.GetValue("LOGIN_BACKOFF_MAX_SECONDS"))
+
+	var seconds float64 = float64(baseSeconds) * math.Pow(2, float64(failures-1))
+	if seconds > float64(maxSeconds) {
+		seconds = float64(maxSeconds)
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
+
+//resetLoginThrottle removes the failures of the account after a successful login
+func resetLoginThrottle(email string) {
+	database.DB.Delete(&models.LoginThrottle{}, "`key` = ?", accountThrottleKey(email))
+}
+
+//recordLoginEvent inserts the login event into the database
+func recordLoginEvent(event models.LoginEvent) {
+	event.ID = uuid.New().String()
+
+	if err := database.DB.Create(&event).Error; err != nil {
+		log.Println("error when recording login event:", err)
+	}
+}
+
+//UnlockUser removes the lockout of the user account
+func UnlockUser(user models.User, unlockedBy models.User) error {
+	//remove the failures of the account
+	if err := database.DB.Delete(&models.LoginThrottle{}, "`key` = ?", accountThrottleKey(user.Email)).Error; err != nil {
+		return err
+	}
+
+	recordLoginEvent(models.LoginEvent{
+		Type:    models.LoginEventUnlock,
+		Email:   user.Email,
+		UserID:  user.ID,
+		Details: "account is unlocked by " + unlockedBy.Email,
+	})
+
+	return nil
+}
+
+//GetLoginEvents returns the latest login events
+//the events can be filtered by type, email and IP address
+func GetLoginEvents(eventType string, email string, ipAddress string, limit int) []models.LoginEvent {
+	// create a variable to store login events data
+	var events []models.LoginEvent = []models.LoginEvent{}
+
+	var query *gorm.DB = database.DB.Order("created_at desc").Limit(limit)
+
+	if eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+	if email != "" {
+		query = query.Where("email = ?", email)
+	}
+	if ipAddress != "" {
+		query = query.Where("ip_address = ?", ipAddress)
+	}
+
+	query.Find(&events)
+
+	return events
+}
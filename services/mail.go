@@ -0,0 +1,34 @@
+package services
+
+import (
+	"sync"
+
+	"inventory-project-testing/utils"
+)
+
+//mailer delivers the emails sent by the services
+var (
+	mailer      utils.Mailer
+	mailerMutex sync.Mutex
+)
+
+//SetMailer replaces the mailer used by the services
+func SetMailer(newMailer utils.Mailer) {
+	mailerMutex.Lock()
+	defer mailerMutex.Unlock()
+
+	mailer = newMailer
+}
+
+//getMailer returns the mailer used by the services
+//the mailer is created from the configuration if it is not set
+func getMailer() utils.Mailer {
+	mailerMutex.Lock()
+	defer mailerMutex.Unlock()
+
+	if mailer == nil {
+		mailer = utils.NewMailer()
+	}
+
+	return mailer
+}
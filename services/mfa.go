@@ -0,0 +1,248 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrInvalidMFACode is returned when the TOTP code or the recovery code is invalid
+var ErrInvalidMFACode = errors.New("invalid mfa code")
+
+//EnrollTOTP creates a new TOTP secret for the user
+//the TOTP is enabled after the enrollment is confirmed with a valid code
+func EnrollTOTP(user models.User) (models.TOTPEnrollment, error) {
+	//if the TOTP is already enabled, return an error
+	if user.TOTPEnabledAt != nil {
+		return models.TOTPEnrollment{}, errors.New("totp is already enabled")
+	}
+
+	//generate a new TOTP secret
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return models.TOTPEnrollment{}, err
+	}
+
+	//store the pending TOTP secret
+	if err := database.DB.Model(&user).Update("totp_secret", secret).Error; err != nil {
+		return models.TOTPEnrollment{}, err
+	}
+
+	//create the otpauth URI and its QR code
+	var uri string = utils.GetTOTPURI(utils.GetValue("JWT_ISSUER"), user.Email, secret)
+
+	qrCode, err := utils.GenerateQRCodePNG(uri, 256)
+	if err != nil {
+		return models.TOTPEnrollment{}, err
+	}
+
+	return models.TOTPEnrollment{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCodePNG:  qrCode,
+	}, nil
+}
+
+//ConfirmTOTP enables the TOTP and returns new recovery codes
+func ConfirmTOTP(user models.User, code string) (models.RecoveryCodes, error) {
+	//if the enrollment is not started, return an error
+	if user.TOTPSecret == "" || user.TOTPEnabledAt != nil {
+		return models.RecoveryCodes{}, errors.New("totp enrollment is not started")
+	}
+
+	//if the code is invalid, return an error
+	step, ok := utils.ValidateTOTPCode(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return models.RecoveryCodes{}, ErrInvalidMFACode
+	}
+
+	var recoveryCodes []string
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		//enable the TOTP
+		if err := tx.Model(&user).Updates(map[string]any{
+			"totp_enabled_at":     time.Now(),
+			"totp_last_used_step": step,
+		}).Error; err != nil {
+			return err
+		}
+
+		//create the recovery codes
+		var err error
+		recoveryCodes, err = createRecoveryCodes(tx, user.ID)
+		return err
+	})
+
+	if err != nil {
+		return models.RecoveryCodes{}, err
+	}
+
+	return models.RecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+//DisableTOTP disables the TOTP after the code is verified
+func DisableTOTP(user models.User, code string) error {
+	//if the TOTP is not enabled, return an error
+	if user.TOTPEnabledAt == nil {
+		return errors.New("totp is not enabled")
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		//verify the second factor
+		if err := verifySecondFactor(tx, user, code); err != nil {
+			return err
+		}
+
+		//remove the recovery codes
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		//remove the TOTP secret
+		return tx.Model(&user).Updates(map[string]any{
+			"totp_secret":         "",
+			"totp_enabled_at":     nil,
+			"totp_last_used_step": 0,
+		}).Error
+	})
+}
+
+//LoginWithMFA returns JWT token pair after the second factor is verified
+func LoginWithMFA(mfaInput models.MFALoginRequest, ipAddress string) (models.TokenPair, error) {
+	//get the user from the MFA token
+	userID, err := utils.ParseMFAToken(mfaInput.MFAToken)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//if the account is disabled, return the error
+	if user.DisabledAt != nil {
+		return models.TokenPair{}, ErrAccountDisabled
+	}
+
+	//if the login is temporarily blocked, return the error
+	if err := checkLoginThrottle(user.Email, ipAddress); err != nil {
+		return models.TokenPair{}, err
+	}
+
+	var tokenPair models.TokenPair
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		//verify the second factor
+		if err := verifySecondFactor(tx, user, mfaInput.Code); err != nil {
+			return err
+		}
+
+		//generate the JWT token pair with a new token family
+		var err error
+		tokenPair, err = issueTokenPair(tx, user, getDefaultOrganizationID(user.ID), uuid.New().String())
+		return err
+	})
+
+	//count the invalid codes as failed logins
+	if errors.Is(err, ErrInvalidMFACode) {
+		recordLoginFailure(user.Email, ipAddress, user.ID)
+		return models.TokenPair{}, err
+	}
+
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//the failed attempts of the account are reset after a successful login
+	resetLoginThrottle(user.Email)
+	return tokenPair, nil
+}
+
+//verifySecondFactor checks the TOTP code or the recovery code of the user
+//every code can only be used once
+func verifySecondFactor(tx *gorm.DB, user models.User, code string) error {
+	//lock the user so the same code cannot be used concurrently
+	var lockedUser models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lockedUser, "id = ?", user.ID).Error; err != nil {
+		return err
+	}
+
+	//accept the TOTP code if it is newer than the last used code
+	if step, ok := utils.ValidateTOTPCode(lockedUser.TOTPSecret, code, time.Now()); ok {
+		if step <= lockedUser.TOTPLastUsedStep {
+			return ErrInvalidMFACode
+		}
+
+		return tx.Model(&lockedUser).Update("totp_last_used_step", step).Error
+	}
+
+	//otherwise, accept an unused recovery code
+	result := tx.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, utils.HashToken(normalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+//createRecoveryCodes replaces the recovery codes of the user
+func createRecoveryCodes(tx *gorm.DB, userID string) ([]string, error) {
+	//remove the previous recovery codes
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	//get the number of the recovery codes from .env file
+	count, _ := strconv.Atoi(utils.GetValue("MFA_RECOVERY_CODES_COUNT"))
+
+	var codes []string
+	for i := 0; i < count; i++ {
+		//generate a random recovery code, for example "a1b2c-3d4e5"
+		var randomBytes []byte = make([]byte, 5)
+		if _, err := rand.Read(randomBytes); err != nil {
+			return nil, err
+		}
+
+		var encoded string = hex.EncodeToString(randomBytes)
+		var code string = encoded[:5] + "-" + encoded[5:]
+
+		//only the hash of the recovery code is stored into the database
+		var recoveryCode models.RecoveryCode = models.RecoveryCode{
+			ID:       uuid.New().String(),
+			UserID:   userID,
+			CodeHash: utils.HashToken(normalizeRecoveryCode(code)),
+		}
+
+		if err := tx.Create(&recoveryCode).Error; err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+//normalizeRecoveryCode removes the formatting of the recovery code
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"gorm.io/gorm"
+)
+
+//defaultMovementLimit is the number of movements returned if the limit is not provided
+const defaultMovementLimit = 100
+
+//CreateStockMovement records the movement of the item and changes its stock
+//the transfer records a movement for both locations in the same transaction
+func CreateStockMovement(organizationID string, userID string, itemID string, movementInput models.StockMovementRequest) ([]models.StockMovement, error) {
+	var movements []models.StockMovement = []models.StockMovement{}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockItemStock(tx, organizationID, itemID); err != nil {
+			return err
+		}
+
+		//if the locations are not found, return an error
+		if _, err := getLocation(tx, organizationID, movementInput.LocationID); err != nil {
+			return err
+		}
+
+		if movementInput.Type == models.MovementTypeTransfer {
+			if _, err := getLocation(tx, organizationID, movementInput.ToLocationID); err != nil {
+				return err
+			}
+		}
+
+		var movement models.StockMovement = models.StockMovement{
+			OrganizationID: organizationID,
+			ItemID:         itemID,
+			LocationID:     movementInput.LocationID,
+			Type:           movementInput.Type,
+			Quantity:       movementInput.Quantity,
+			ReasonCode:     movementInput.ReasonCode,
+			Reference:      movementInput.Reference,
+			UserID:         userID,
+		}
+
+		//the issue and the transfer remove the stock from the location
+		if movementInput.Type == models.MovementTypeIssue || movementInput.Type == models.MovementTypeTransfer {
+			movement.Quantity = -movementInput.Quantity
+		}
+
+		recorded, err := adjustStock(tx, movement)
+		if err != nil {
+			return err
+		}
+
+		movements = append(movements, recorded)
+
+		if movementInput.Type != models.MovementTypeTransfer {
+			//the removed stock cannot be held by the reservations
+			if movement.Quantity < 0 {
+				return checkReservedStock(tx, itemID)
+			}
+
+			return nil
+		}
+
+		//add the transferred stock into the other location
+		movement.LocationID = movementInput.ToLocationID
+		movement.Quantity = movementInput.Quantity
+
+		recorded, err = adjustStock(tx, movement)
+		if err != nil {
+			return err
+		}
+
+		movements = append(movements, recorded)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+//GetItemMovements returns the movements of the item with the newest movements first
+func GetItemMovements(organizationID string, itemID string, movementQuery models.MovementQuery) ([]models.StockMovement, error) {
+	var item models.Item
+	if tenantDB(organizationID).Limit(1).Find(&item, "id = ?", itemID).RowsAffected == 0 {
+		return nil, ErrItemNotFound
+	}
+
+	// use the default limit if the limit is not provided
+	var limit int = movementQuery.Limit
+	if limit == 0 {
+		limit = defaultMovementLimit
+	}
+
+	var query *gorm.DB = tenantDB(organizationID).Where("item_id = ?", itemID)
+	query = filterDateRange(query, "created_at", movementQuery.From, movementQuery.To)
+
+	if movementQuery.Type != "" {
+		query = query.Where("type = ?", movementQuery.Type)
+	}
+
+	if movementQuery.LocationID != "" {
+		query = query.Where("location_id = ?", movementQuery.LocationID)
+	}
+
+	var movements []models.StockMovement = []models.StockMovement{}
+	if err := query.Order("created_at desc").Order("id desc").Limit(limit).Find(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrInvalidOIDCState is returned when the login state is unknown, used or expired
+var ErrInvalidOIDCState = errors.New("oidc login state is invalid or expired")
+
+//ErrOIDCUserNotFound is returned when the identity is not linked and auto provisioning is disabled
+var ErrOIDCUserNotFound = errors.New("no account is linked to the identity")
+
+//oidcProvider is the OpenID Connect provider used by the services
+var (
+	oidcProvider      *utils.OIDCProvider
+	oidcProviderMutex sync.Mutex
+)
+
+//SetOIDCProvider replaces the OpenID Connect provider used by the services
+func SetOIDCProvider(provider *utils.OIDCProvider) {
+	oidcProviderMutex.Lock()
+	defer oidcProviderMutex.Unlock()
+
+	oidcProvider = provider
+}
+
+//getOIDCProvider returns the OpenID Connect provider used by the services
+//the provider is discovered from the configuration if it is not set
+func getOIDCProvider() (*utils.OIDCProvider, error) {
+	oidcProviderMutex.Lock()
+	defer oidcProviderMutex.Unlock()
+
+	if oidcProvider != nil {
+		return oidcProvider, nil
+	}
+
+	//if the provider is not configured, return an error
+	var issuer string = utils.GetValue("OIDC_ISSUER_URL")
+	if issuer == "" {
+		return nil, errors.New("oidc login is not configured")
+	}
+
+	provider, err := utils.DiscoverOIDCProvider(
+		issuer,
+		utils.GetValue("OIDC_CLIENT_ID"),
+		utils.GetValue("OIDC_CLIENT_SECRET"),
+		utils.GetValue("OIDC_REDIRECT_URL"),
+		strings.Fields(utils.GetValue("OIDC_SCOPES")),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	oidcProvider = provider
+	return oidcProvider, nil
+}
+
+//StartOIDCLogin returns the URL of the provider to start the login
+//the state, nonce and PKCE code verifier are stored until the callback
+func StartOIDCLogin() (string, error) {
+	provider, err := getOIDCProvider()
+	if err != nil {
+		return "", err
+	}
+
+	//create the random values of the login
+	state, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	codeVerifier, codeChallenge, err := utils.GeneratePKCE()
+	if err != nil {
+		return "", err
+	}
+
+	//get the login state expire time from .env file
+	minutesCount, _ := strconv.Atoi(utils.GetValue("OIDC_STATE_EXPIRE_MINUTES_COUNT"))
+
+	//only the hash of the state is stored into the database
+	var oidcState models.OIDCState = models.OIDCState{
+		ID:           uuid.New().String(),
+		StateHash:    utils.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(time.Minute * time.Duration(minutesCount)),
+	}
+
+	if err := database.DB.Create(&oidcState).Error; err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(state, nonce, codeChallenge), nil
+}
+
+//CompleteOIDCLogin returns JWT token pair for the identity returned by the provider
+//the identity is linked to the user with the same verified email
+//a new user is created if OIDC_AUTO_PROVISION is enabled
+func CompleteOIDCLogin(state string, code string) (models.TokenPair, error) {
+	provider, err := getOIDCProvider()
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//use the login state, the state cannot be used twice
+	var oidcState models.OIDCState
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&oidcState, "state_hash = ?", utils.HashToken(state))
+		if result.RowsAffected == 0 {
+			return ErrInvalidOIDCState
+		}
+
+		return tx.Delete(&oidcState).Error
+	})
+
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	if time.Now().After(oidcState.ExpiresAt) {
+		return models.TokenPair{}, ErrInvalidOIDCState
+	}
+
+	//exchange the authorization code with the PKCE code verifier
+	idToken, err := provider.Exchange(code, oidcState.CodeVerifier)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//verify the ID token from the provider
+	claims, err := provider.VerifyIDToken(idToken, oidcState.Nonce)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//find or create the user of the identity
+	user, err := getOIDCUser(claims)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//if the account is disabled, return the error
+	if user.DisabledAt != nil {
+		return models.TokenPair{}, ErrAccountDisabled
+	}
+
+	//generate the same JWT token pair as the password login
+	return issueTokenPair(database.DB, user, getDefaultOrganizationID(user.ID), uuid.New().String())
+}
+
+//getOIDCUser returns the user linked to the identity
+func getOIDCUser(claims utils.OIDCClaims) (models.User, error) {
+	var user models.User
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		//if the identity is already linked, return its user
+		var identity models.Identity
+		if tx.First(&identity, "issuer = ? AND subject = ?", claims.Issuer, claims.Subject).RowsAffected > 0 {
+			if tx.First(&user, "id = ?", identity.UserID).RowsAffected == 0 {
+				return ErrOIDCUserNotFound
+			}
+
+			return nil
+		}
+
+		//only the verified email can be used to find the user
+		if claims.Email == "" || !claims.EmailVerified {
+			return ErrOIDCUserNotFound
+		}
+
+		if tx.First(&user, "email = ?", claims.Email).RowsAffected == 0 {
+			//if the auto provisioning is disabled, return the error
+			autoProvision, _ := strconv.ParseBool(utils.GetValue("OIDC_AUTO_PROVISION"))
+			if !autoProvision {
+				return ErrOIDCUserNotFound
+			}
+
+			//create a new user without a password
+			//the user can only log in with the provider
+			var now time.Time = time.Now()
+			user = models.User{
+				ID:              uuid.New().String(),
+				Email:           claims.Email,
+				DisplayName:     claims.Name,
+				Role:            models.RoleViewer,
+				EmailVerifiedAt: &now,
+			}
+
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		}
+
+		//link the identity to the user
+		return tx.Create(&models.Identity{
+			ID:      uuid.New().String(),
+			UserID:  user.ID,
+			Issuer:  claims.Issuer,
+			Subject: claims.Subject,
+			Email:   claims.Email,
+		}).Error
+	})
+
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+//PurgeExpiredOIDCStates removes the login states that are not used before they expire
+func PurgeExpiredOIDCStates() {
+	result := database.DB.Where("expires_at < ?", time.Now()).Delete(&models.OIDCState{})
+
+	//if the purge is failed, print out the error
+	if result.Error != nil {
+		log.Println("error when purging oidc states:", result.Error)
+	}
+}
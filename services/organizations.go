@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrMembershipNotFound is returned when the user is not a member of the organization
+var ErrMembershipNotFound = errors.New("you are not a member of the organization")
+
+//tenantDB returns a database session that only sees the data of the organization
+//every query of the tenant data must be started from this session
+func tenantDB(organizationID string) *gorm.DB {
+	return database.DB.Where("organization_id = ?", organizationID).Session(&gorm.Session{})
+}
+
+//lockOrganization locks the organization until the transaction is finished
+//the changes that must not run at the same time inside the organization are applied one by one
+func lockOrganization(tx *gorm.DB, organizationID string) error {
+	var organization models.Organization
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&organization, "id = ?", organizationID).Error
+}
+
+//GetMembership returns the membership of the user in the organization
+func GetMembership(organizationID string, userID string) (models.Membership, error) {
+	var membership models.Membership
+
+	//find the membership of the user
+	result := database.DB.First(&membership, "organization_id = ? AND user_id = ?", organizationID, userID)
+
+	//if the membership is not found, return the error
+	if result.RowsAffected == 0 {
+		return models.Membership{}, ErrMembershipNotFound
+	}
+
+	return membership, nil
+}
+
+//getDefaultOrganizationID returns the organization selected after the login
+//the oldest membership of the user is used, empty is returned if the user has no membership
+func getDefaultOrganizationID(userID string) string {
+	var membership models.Membership
+	database.DB.Order("created_at asc").Limit(1).Find(&membership, "user_id = ?", userID)
+	return membership.OrganizationID
+}
+
+//GetOrganizations returns the organizations of the user with the role of the user
+func GetOrganizations(userID string) []models.Organization {
+	var organizations []models.Organization = []models.Organization{}
+
+	database.DB.Model(&models.Organization{}).
+		Select("organizations.*, memberships.role").
+		Joins("JOIN memberships ON memberships.organization_id = organizations.id").
+		Where("memberships.user_id = ?", userID).
+		Order("organizations.name asc").
+		Find(&organizations)
+
+	return organizations
+}
+
+//CreateOrganization creates a new organization
+//the user who creates the organization becomes the admin of the organization
+func CreateOrganization(user models.User, organizationInput models.OrganizationRequest) (models.Organization, error) {
+	var organization models.Organization = models.Organization{
+		ID:   uuid.New().String(),
+		Name: organizationInput.Name,
+		Slug: organizationInput.Slug,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		//if the slug is already used, return the error
+		var count int64
+		tx.Model(&models.Organization{}).Where("slug = ?", organization.Slug).Count(&count)
+		if count > 0 {
+			return errors.New("slug is already used")
+		}
+
+		//insert the organization into the database
+		if err := tx.Create(&organization).Error; err != nil {
+			return err
+		}
+
+		//add the user as the admin of the organization
+		return tx.Create(&models.Membership{
+			ID:             uuid.New().String(),
+			OrganizationID: organization.ID,
+			UserID:         user.ID,
+			Role:           models.RoleAdmin,
+		}).Error
+	})
+
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	organization.Role = models.RoleAdmin
+	return organization, nil
+}
+
+//SwitchOrganization returns a new token pair for the selected organization
+func SwitchOrganization(user models.User, organizationID string) (models.TokenPair, error) {
+	//make sure the user is a member of the organization
+	if _, err := GetMembership(organizationID, user.ID); err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//generate the JWT token pair with a new token family
+	return issueTokenPair(database.DB, user, organizationID, uuid.New().String())
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+//ForgotPassword sends the password reset email to the user
+//no error is returned if the email is not registered
+func ForgotPassword(forgotInput models.ForgotPasswordRequest) error {
+	//create a variable called "user"
+	var user models.User
+
+	//find the user based on them email
+	result := database.DB.First(&user, "email = ?", forgotInput.Email)
+
+	//if the user is not found, do nothing
+	//so the registered emails cannot be discovered
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	//get the reset token expire time from .env file
+	minutesCount, _ := strconv.Atoi(utils.GetValue("PASSWORD_RESET_EXPIRE_MINUTES_COUNT"))
+
+	//create a new reset token
+	token, err := issueUserToken(database.DB, user.ID, models.TokenPurposePasswordReset, time.Minute*time.Duration(minutesCount))
+	if err != nil {
+		return err
+	}
+
+	//send the reset token to the user
+	return getMailer().Send(utils.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: "Use the link below to reset your password. The link expires in " + strconv.Itoa(minutesCount) + " minutes.\n\n" +
+			utils.GetValue("APP_URL") + "/reset-password?token=" + token,
+	})
+}
+
+//ResetPassword sets a new password with the reset token
+//every session of the user is revoked after the password is changed
+func ResetPassword(resetInput models.ResetPasswordRequest) error {
+	//create a password using bcrypt Library
+	password, err := bcrypt.GenerateFromPassword([]byte(resetInput.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		//use the reset token
+		userToken, err := consumeUserToken(tx, resetInput.Token, models.TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		//update the password of the user
+		if err := tx.Model(&models.User{}).Where("id = ?", userToken.UserID).Update("password", string(password)).Error; err != nil {
+			return err
+		}
+
+		//revoke the sessions that were created with the old password
+		return revokeAllSessions(tx, userToken.UserID)
+	})
+}
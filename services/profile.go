@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+//ErrInvalidCurrentPassword is returned when the current password is not matched
+var ErrInvalidCurrentPassword = errors.New("current password is invalid")
+
+//UpdateProfile changes the profile of the user
+//only the profile columns are updated so the concurrent changes of the other columns are kept
+func UpdateProfile(user models.User, profileInput models.UpdateProfileRequest) (models.User, error) {
+	//update the provided fields only
+	var profile map[string]any = map[string]any{}
+
+	if profileInput.DisplayName != nil {
+		profile["display_name"] = *profileInput.DisplayName
+	}
+
+	if profileInput.Locale != nil {
+		profile["locale"] = *profileInput.Locale
+	}
+
+	if profileInput.Timezone != nil {
+		profile["timezone"] = *profileInput.Timezone
+	}
+
+	//save the profile into the database
+	if len(profile) > 0 {
+		if err := database.DB.Model(&models.User{ID: user.ID}).Updates(profile).Error; err != nil {
+			return models.User{}, err
+		}
+	}
+
+	//return the current data of the user
+	var updatedUser models.User
+	if err := database.DB.First(&updatedUser, "id = ?", user.ID).Error; err != nil {
+		return models.User{}, err
+	}
+
+	return updatedUser, nil
+}
+
+//ChangePassword changes the password of the user after the current password is verified
+//every session of the user is revoked and a new token pair is returned for the caller
+//the new token pair keeps the organization of the caller
+func ChangePassword(user models.User, organizationID string, passwordInput models.ChangePasswordRequest) (models.TokenPair, error) {
+	//if the current password is not matched, return the error
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(passwordInput.CurrentPassword)); err != nil {
+		return models.TokenPair{}, ErrInvalidCurrentPassword
+	}
+
+	//create a password using bcrypt Library
+	password, err := bcrypt.GenerateFromPassword([]byte(passwordInput.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	var tokenPair models.TokenPair
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		//update the password of the user
+		if err := tx.Model(&user).Update("password", string(password)).Error; err != nil {
+			return err
+		}
+
+		//log out the other sessions of the user
+		if err := revokeAllSessions(tx, user.ID); err != nil {
+			return err
+		}
+
+		//generate the JWT token pair with a new token family
+		var err error
+		tokenPair, err = issueTokenPair(tx, user, organizationID, uuid.New().String())
+		return err
+	})
+
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return tokenPair, nil
+}
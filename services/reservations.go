@@ -0,0 +1,271 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrReservationNotFound is returned when the reservation is not found in the organization
+var ErrReservationNotFound = errors.New("reservation not found")
+
+//ErrReservationNotActive is returned when the reservation is already released, fulfilled or expired
+var ErrReservationNotActive = errors.New("reservation is not active")
+
+//ErrInsufficientAvailability is returned when the quantity is more than the available quantity of the item
+var ErrInsufficientAvailability = errors.New("insufficient available quantity of the item")
+
+//ErrStockReserved is returned when the stock that is held by the active reservations would be removed
+var ErrStockReserved = errors.New("the stock is held by active reservations")
+
+//defaultReservationMinutes is the expiry of the reservation if RESERVATION_EXPIRE_MINUTES_COUNT is not configured
+const defaultReservationMinutes = 60
+
+//activeReservations returns the query of the reservations that still hold the stock
+func activeReservations(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&models.Reservation{}).Where("status = ? AND expires_at > ?", models.ReservationStatusActive, time.Now())
+}
+
+//getReservedQuantity returns the quantity of the item that is held by the active reservations
+func getReservedQuantity(tx *gorm.DB, itemID string) int {
+	var quantity int
+	activeReservations(tx).Select("COALESCE(SUM(quantity), 0)").Where("item_id = ?", itemID).Scan(&quantity)
+	return quantity
+}
+
+//checkReservedStock returns an error if the stock of the item is less than its reserved quantity
+//it is called after the stock is removed, the item must be locked with lockItemStock first
+func checkReservedStock(tx *gorm.DB, itemID string) error {
+	var item models.Item
+	tx.Select("quantity").Limit(1).Find(&item, "id = ?", itemID)
+
+	if item.Quantity < getReservedQuantity(tx, itemID) {
+		return ErrStockReserved
+	}
+
+	return nil
+}
+
+//setItemAvailability fills the quantities on hand, reserved and available of the items
+func setItemAvailability(items []models.Item) {
+	if len(items) == 0 {
+		return
+	}
+
+	var itemIDs []string
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	//get the reserved quantity of every item with one query
+	var reserved []struct {
+		ItemID   string
+		Quantity int
+	}
+
+	activeReservations(database.DB).
+		Select("item_id, SUM(quantity) AS quantity").
+		Where("item_id IN ?", itemIDs).
+		Group("item_id").
+		Scan(&reserved)
+
+	var reservedQuantities map[string]int = map[string]int{}
+	for _, reservation := range reserved {
+		reservedQuantities[reservation.ItemID] = reservation.Quantity
+	}
+
+	for i := range items {
+		items[i].OnHand = items[i].Quantity
+		items[i].Reserved = reservedQuantities[items[i].ID]
+		items[i].Available = items[i].OnHand - items[i].Reserved
+
+		if items[i].Available < 0 {
+			items[i].Available = 0
+		}
+
+		setItemAvailability(items[i].Variants)
+	}
+}
+
+//GetReservations returns the reservations of the organization with the newest reservations first
+func GetReservations(organizationID string, reservationQuery models.ReservationQuery) []models.Reservation {
+	var query *gorm.DB = tenantDB(organizationID)
+
+	if reservationQuery.ItemID != "" {
+		query = query.Where("item_id = ?", reservationQuery.ItemID)
+	}
+
+	if reservationQuery.Status != "" {
+		query = query.Where("status = ?", reservationQuery.Status)
+	}
+
+	var reservations []models.Reservation = []models.Reservation{}
+	query.Order("created_at desc").Find(&reservations)
+
+	return reservations
+}
+
+//CreateReservation holds the stock of the item until the reservation expires
+//the item is locked so the concurrent reservations cannot reserve the same stock
+func CreateReservation(organizationID string, userID string, reservationInput models.ReservationRequest) (models.Reservation, error) {
+	//use the default expiry if the expiry is not provided
+	var minutes int = reservationInput.ExpiresInMinutes
+	if minutes == 0 {
+		minutes, _ = strconv.Atoi(utils.GetValue("RESERVATION_EXPIRE_MINUTES_COUNT"))
+	}
+
+	if minutes <= 0 {
+		minutes = defaultReservationMinutes
+	}
+
+	var now time.Time = time.Now()
+	var reservation models.Reservation = models.Reservation{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		ItemID:         reservationInput.ItemID,
+		Quantity:       reservationInput.Quantity,
+		Reference:      reservationInput.Reference,
+		Status:         models.ReservationStatusActive,
+		ExpiresAt:      now.Add(time.Minute * time.Duration(minutes)),
+		CreatedBy:      userID,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		item, err := lockItemStock(tx, organizationID, reservationInput.ItemID)
+		if err != nil {
+			return err
+		}
+
+		//the reservations are read after the item is locked so the reservations of the previous lock holder are included
+		//if the quantity is more than the available quantity, return an error
+		if item.Quantity-getReservedQuantity(tx, item.ID) < reservationInput.Quantity {
+			return ErrInsufficientAvailability
+		}
+
+		return tx.Create(&reservation).Error
+	})
+
+	if err != nil {
+		return models.Reservation{}, err
+	}
+
+	return reservation, nil
+}
+
+//lockReservation locks the active reservation until its status is changed
+func lockReservation(tx *gorm.DB, organizationID string, id string) (models.Reservation, error) {
+	var reservation models.Reservation
+
+	if tx.Clauses(clause.Locking{Strength: "UPDATE"}).Limit(1).Find(&reservation, "organization_id = ? AND id = ?", organizationID, id).RowsAffected == 0 {
+		return models.Reservation{}, ErrReservationNotFound
+	}
+
+	if reservation.Status != models.ReservationStatusActive || !reservation.ExpiresAt.After(time.Now()) {
+		return models.Reservation{}, ErrReservationNotActive
+	}
+
+	return reservation, nil
+}
+
+//ReleaseReservation releases the stock that is held by the reservation
+func ReleaseReservation(organizationID string, id string) (models.Reservation, error) {
+	var reservation models.Reservation
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		reservation, err = lockReservation(tx, organizationID, id)
+		if err != nil {
+			return err
+		}
+
+		var now time.Time = time.Now()
+		reservation.Status = models.ReservationStatusReleased
+		reservation.ReleasedAt = &now
+
+		return tx.Save(&reservation).Error
+	})
+
+	if err != nil {
+		return models.Reservation{}, err
+	}
+
+	return reservation, nil
+}
+
+//FulfillReservation issues the reserved stock from the location
+//the issue is recorded in the ledger with the reference of the reservation
+func FulfillReservation(organizationID string, userID string, id string, fulfillInput models.FulfillReservationRequest) (models.Reservation, error) {
+	var reservation models.Reservation
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		reservation, err = lockReservation(tx, organizationID, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := lockItemStock(tx, organizationID, reservation.ItemID); err != nil {
+			return err
+		}
+
+		if _, err := getLocation(tx, organizationID, fulfillInput.LocationID); err != nil {
+			return err
+		}
+
+		var reference string = reservation.Reference
+		if reference == "" {
+			reference = reservation.ID
+		}
+
+		_, err = adjustStock(tx, models.StockMovement{
+			OrganizationID: organizationID,
+			ItemID:         reservation.ItemID,
+			LocationID:     fulfillInput.LocationID,
+			Type:           models.MovementTypeIssue,
+			Quantity:       -reservation.Quantity,
+			ReasonCode:     models.ReasonReservationFulfilled,
+			Reference:      reference,
+			UserID:         userID,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		var now time.Time = time.Now()
+		reservation.Status = models.ReservationStatusFulfilled
+		reservation.ReleasedAt = &now
+
+		return tx.Save(&reservation).Error
+	})
+
+	if err != nil {
+		return models.Reservation{}, err
+	}
+
+	return reservation, nil
+}
+
+//ReleaseExpiredReservations marks the active reservations that are expired as expired
+//the expired reservations do not hold the stock even before they are released
+func ReleaseExpiredReservations() {
+	var now time.Time = time.Now()
+
+	result := database.DB.Model(&models.Reservation{}).
+		Where("status = ? AND expires_at <= ?", models.ReservationStatusActive, now).
+		Updates(map[string]any{"status": models.ReservationStatusExpired, "released_at": now})
+
+	//if the release is failed, print out the error
+	if result.Error != nil {
+		log.Println("error when releasing expired reservations:", result.Error)
+	}
+}
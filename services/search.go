@@ -0,0 +1,144 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+)
+
+//itemFieldWeights are the weights of the item fields in the search index
+var itemFieldWeights = map[string]float64{
+	"name":        3,
+	"sku":         3,
+	"tags":        2,
+	"attributes":  1,
+	"description": 1,
+}
+
+//searchIndexes contains the search index of every organization
+//the index of the organization is built when it is used for the first time
+var (
+	searchIndexes      map[string]*utils.SearchIndex = map[string]*utils.SearchIndex{}
+	searchIndexesMutex sync.Mutex
+)
+
+//getSearchIndex returns the search index of the organization
+func getSearchIndex(organizationID string) *utils.SearchIndex {
+	searchIndexesMutex.Lock()
+	defer searchIndexesMutex.Unlock()
+
+	if index, ok := searchIndexes[organizationID]; ok {
+		return index
+	}
+
+	//build the index from the items of the organization
+	var index *utils.SearchIndex = utils.NewSearchIndex(itemFieldWeights)
+
+	var items []models.Item
+	tenantDB(organizationID).Find(&items)
+
+	for _, item := range items {
+		index.Index(getItemDocument(item))
+	}
+
+	searchIndexes[organizationID] = index
+	return index
+}
+
+//getItemDocument returns the search document of the item
+func getItemDocument(item models.Item) utils.SearchDocument {
+	return utils.SearchDocument{
+		ID: item.ID,
+		Fields: map[string]string{
+			"name":        item.Name,
+			"sku":         item.SKU,
+			"tags":        strings.Join(item.Tags, " "),
+			"attributes":  getAttributeText(item.Attributes),
+			"description": item.Description,
+		},
+	}
+}
+
+//getAttributeText returns the text values of the attributes
+func getAttributeText(attributes map[string]any) string {
+	var values []string
+
+	for _, value := range attributes {
+		if text, ok := value.(string); ok {
+			values = append(values, text)
+		}
+	}
+
+	sort.Strings(values)
+	return strings.Join(values, " ")
+}
+
+//indexItem adds or updates the item in the search index of its organization
+func indexItem(item models.Item) {
+	getSearchIndex(item.OrganizationID).Index(getItemDocument(item))
+}
+
+//removeIndexedItem removes the item from the search index of its organization
+func removeIndexedItem(item models.Item) {
+	getSearchIndex(item.OrganizationID).Remove(item.ID)
+}
+
+//SearchItems returns the items of the organization that match the query
+//the items are ordered by the relevance of the match
+func SearchItems(organizationID string, query string, limit int) []models.ItemSearchResult {
+	var hits []utils.SearchHit = getSearchIndex(organizationID).Search(query, limit)
+
+	//get the matched items from the database
+	var ids []string
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+
+	var items []models.Item
+	if len(ids) > 0 {
+		tenantDB(organizationID).Where("id IN ?", ids).Find(&items)
+	}
+
+	//fill the quantities on hand, reserved and available of the items
+	setItemAvailability(items)
+
+	var itemsByID map[string]models.Item = map[string]models.Item{}
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	//keep the order of the search results
+	//the items that are already deleted are skipped
+	var results []models.ItemSearchResult = []models.ItemSearchResult{}
+	for _, hit := range hits {
+		item, ok := itemsByID[hit.ID]
+		if !ok {
+			continue
+		}
+
+		results = append(results, models.ItemSearchResult{
+			Item:       item,
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+		})
+	}
+
+	return results
+}
+
+//SuggestItems returns the item names that start with the prefix
+func SuggestItems(organizationID string, prefix string, limit int) []models.ItemSuggestion {
+	var suggestions []models.ItemSuggestion = []models.ItemSuggestion{}
+
+	for _, suggestion := range getSearchIndex(organizationID).Suggest(prefix, "name", limit) {
+		suggestions = append(suggestions, models.ItemSuggestion{
+			ID:   suggestion.ID,
+			Name: suggestion.Text,
+		})
+	}
+
+	return suggestions
+}
@@ -1,96 +1,393 @@
-package services
-
-import (
-	"errors"
-	"time"
-
-	"github.com/google/uuid"
-	"inventory-project-testing/models"
-	"inventory-project-testing/database"
-)
-
-var storage []models.Item = []models.Item{}
-
-func GetAllItems() []models.Item {
-	// create a variable to store items data
-	var items []models.Item = []models.Item{}
-
-	// get all data from the database order by created_at
-	database.DB.Order("created_at desc").Find(&items)
-
-	// return all items from the database
-	return items
-}
-
-func GetItemByID(id string) (models.Item, error) {
-	// create a variable to store item data
-	var item models.Item
-
-	// get item data from the database by ID
-	result := database.DB.First(&item, "id = ?", id)
-
-	// if the item data is not found, return an error
-	if result.RowsAffected == 0 {
-		return models.Item{}, errors.New("item not found")
-	}
-
-	// return the item data from the database
-	return item, nil
-}
-
-func CreateItem(itemRequest models.ItemRequest) models.Item {
-	// create a new item
-	// this item will be inserted to the database
-	var newItem models.Item = models.Item{
-		ID:        uuid.New().String(),
-		Name:      itemRequest.Name,
-		Price:     itemRequest.Price,
-		Quantity:  itemRequest.Quantity,
-		CreatedAt: time.Now(),
-	}
-
-	// insert the new item data into the database
-	database.DB.Create(&newItem)
-
-	// return the recently inserted item
-	return newItem
-}
-
-func UpdateItem(itemRequest models.ItemRequest, id string) (models.Item, error) {
-	// get the item data by ID
-	item, err := GetItemByID(id)
-
-	// if item is not found, return an error
-	if err != nil {
-		return models.Item{}, err
-	}
-
-	// update item data
-	item.Name = itemRequest.Name
-	item.Price = itemRequest.Price
-	item.Quantity = itemRequest.Quantity
-	item.UpdatedAt = time.Now()
-
-	// update the item data in the database
-	database.DB.Save(&item)
-
-	// return the updated item
-	return item, nil
-}
-
-func DeleteItem(id string) bool {
-	// get the item data by ID
-	item, err := GetItemByID(id)
-
-	// if item is not found, return false
-	if err != nil {
-		return false
-	}
-
-	// delete the item data
-	database.DB.Delete(&item)
-
-	// return true
-	// this means the deletion is succeed
-	return true
-}
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-project-testing/models"
+	"inventory-project-testing/database"
+	"inventory-project-testing/utils"
+)
+
+var storage []models.Item = []models.Item{}
+
+//GetAllItems returns a page of items of the organization
+//the items after the cursor are returned if the cursor is provided
+func GetAllItems(organizationID string, itemQuery models.ItemQuery) ([]models.Item, models.Pagination, error) {
+	// create a variable to store items data
+	var items []models.Item = []models.Item{}
+
+	// use the default limit if the limit is not provided
+	var limit int = itemQuery.Limit
+	if limit == 0 {
+		limit = defaultItemLimit
+	}
+
+	// filter the items of the organization
+	var query *gorm.DB = filterItems(tenantDB(organizationID).Model(&models.Item{}), itemQuery)
+
+	// filter the items by the category including its descendants
+	if itemQuery.CategoryID != "" {
+		categoryIDs, err := getCategoryTreeIDs(organizationID, itemQuery.CategoryID)
+		if err != nil {
+			return nil, models.Pagination{}, err
+		}
+
+		query = query.Where("category_id IN ?", categoryIDs)
+	}
+
+	query = query.Session(&gorm.Session{})
+
+	// count the items that match the filters
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, models.Pagination{}, err
+	}
+
+	var sorts []models.ItemSort = itemQuery.GetSort()
+	var pageQuery *gorm.DB = query
+
+	// start after the cursor if the cursor is provided
+	if itemQuery.Cursor != "" {
+		cursor, err := decodeItemCursor(itemQuery.Cursor)
+		if err != nil {
+			return nil, models.Pagination{}, err
+		}
+
+		pageQuery, err = seekItems(pageQuery, sorts, cursor)
+		if err != nil {
+			return nil, models.Pagination{}, err
+		}
+	}
+
+	// get one more item to know if there is a next page
+	if err := sortItems(pageQuery, sorts).Preload("Barcodes").Limit(limit + 1).Find(&items).Error; err != nil {
+		return nil, models.Pagination{}, err
+	}
+
+	var pagination models.Pagination = models.Pagination{
+		Limit: limit,
+		Total: total,
+	}
+
+	// if there is a next page, return the cursor of the last item
+	if len(items) > limit {
+		items = items[:limit]
+		pagination.NextCursor = encodeItemCursor(items[limit-1], sorts)
+	}
+
+	// fill the quantities on hand, reserved and available of the items
+	setItemAvailability(items)
+
+	// return the items of the page
+	return items, pagination, nil
+}
+
+func GetItemByID(organizationID string, id string) (models.Item, error) {
+	// create a variable to store item data
+	var item models.Item
+
+	// get item data of the organization from the database by ID
+	// the barcodes of the item are loaded as well
+	result := tenantDB(organizationID).Preload("Barcodes").First(&item, "id = ?", id)
+
+	// if the item data is not found, return an error
+	if result.RowsAffected == 0 {
+		return models.Item{}, ErrItemNotFound
+	}
+
+	// the product is returned with its variants and the total stock of the variants
+	if item.ParentID == nil {
+		if variants := getItemVariants(database.DB, organizationID, item.ID); len(variants) > 0 {
+			var totalQuantity int = item.Quantity
+			for _, variant := range variants {
+				totalQuantity += variant.Quantity
+			}
+
+			item.Variants = variants
+			item.TotalQuantity = &totalQuantity
+		}
+	}
+
+	// fill the quantities on hand, reserved and available of the item and its variants
+	var items []models.Item = []models.Item{item}
+	setItemAvailability(items)
+
+	// return the item data from the database
+	return items[0], nil
+}
+
+func CreateItem(organizationID string, userID string, itemRequest models.ItemRequest) (models.Item, error) {
+	// generate the SKU if it is not provided
+	var sku string = itemRequest.SKU
+	if sku == "" {
+		sku = utils.GenerateSKU()
+	}
+
+	// create a new item
+	// this item will be inserted to the database
+	var itemID string = uuid.New().String()
+	var newItem models.Item = models.Item{
+		ID:        itemID,
+		OrganizationID: organizationID,
+		CategoryID: itemRequest.CategoryID,
+		SKU:       sku,
+		Name:      itemRequest.Name,
+		Description: itemRequest.Description,
+		Price:     itemRequest.Price,
+		Quantity:  itemRequest.Quantity,
+		Tags:      normalizeTags(itemRequest.Tags),
+		Attributes: normalizeAttributes(itemRequest.Attributes),
+		Barcodes:  newItemBarcodes(organizationID, itemID, itemRequest.Barcodes),
+		CreatedAt: time.Now(),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// if the SKU or the barcodes are already used, return an error
+		if err := checkItemIdentifiers(tx, organizationID, newItem.ID, newItem.SKU, itemRequest.Barcodes); err != nil {
+			return err
+		}
+
+		// if the category is not found, return an error
+		if err := checkItemCategory(tx, organizationID, newItem.CategoryID); err != nil {
+			return err
+		}
+
+		// insert the new item data into the database
+		if err := tx.Omit("Barcodes").Create(&newItem).Error; err != nil {
+			return getItemSaveError(err)
+		}
+
+		// insert the barcodes of the item
+		// the unique index rejects the barcode that is used by another item
+		if err := createItemBarcodes(tx, newItem.Barcodes); err != nil {
+			return err
+		}
+
+		// put the initial quantity into the default location
+		if newItem.Quantity == 0 {
+			return nil
+		}
+
+		location, err := getDefaultLocation(tx, organizationID)
+		if err != nil {
+			return err
+		}
+
+		// the initial quantity is recorded as the receipt of the item
+		_, err = adjustStock(tx, models.StockMovement{
+			OrganizationID: organizationID,
+			ItemID:         newItem.ID,
+			LocationID:     location.ID,
+			Type:           models.MovementTypeReceipt,
+			Quantity:       newItem.Quantity,
+			ReasonCode:     models.ReasonInitialStock,
+			UserID:         userID,
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	// add the new item into the search index
+	indexItem(newItem)
+
+	// the new item has no reservations
+	newItem.OnHand = newItem.Quantity
+	newItem.Available = newItem.Quantity
+
+	// return the recently inserted item
+	return newItem, nil
+}
+
+func UpdateItem(organizationID string, userID string, itemRequest models.ItemRequest, id string) (models.Item, error) {
+	// get the item data by ID
+	item, err := GetItemByID(organizationID, id)
+
+	// if item is not found, return an error
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	// keep the SKU if it is not provided
+	// the item that was created before the SKUs existed gets a new SKU
+	if itemRequest.SKU != "" {
+		item.SKU = itemRequest.SKU
+	} else if item.SKU == "" {
+		item.SKU = utils.GenerateSKU()
+	}
+
+	// update item data
+	item.CategoryID = itemRequest.CategoryID
+	item.Name = itemRequest.Name
+	item.Description = itemRequest.Description
+	item.Price = itemRequest.Price
+	item.Quantity = itemRequest.Quantity
+	item.Tags = normalizeTags(itemRequest.Tags)
+	item.Attributes = normalizeAttributes(itemRequest.Attributes)
+	item.Barcodes = newItemBarcodes(organizationID, item.ID, itemRequest.Barcodes)
+	item.UpdatedAt = time.Now()
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// if the SKU or the barcodes are used by another item, return an error
+		if err := checkItemIdentifiers(tx, organizationID, item.ID, item.SKU, itemRequest.Barcodes); err != nil {
+			return err
+		}
+
+		// if the category is not found, return an error
+		if err := checkItemCategory(tx, organizationID, item.CategoryID); err != nil {
+			return err
+		}
+
+		// lock the stock of the item
+		// the quantity is the sum of the stock levels, so the change is applied to the default location
+		lockedItem, err := lockItemStock(tx, organizationID, item.ID)
+		if err != nil {
+			return err
+		}
+
+		var delta int = itemRequest.Quantity - lockedItem.Quantity
+
+		// the change cannot be applied to the default location if the stock is split between the locations
+		if delta != 0 {
+			var locations int64
+			if err := tx.Model(&models.StockLevel{}).Where("item_id = ? AND quantity <> 0", item.ID).Count(&locations).Error; err != nil {
+				return err
+			}
+
+			if locations > 1 {
+				return ErrQuantityByLocation
+			}
+		}
+
+		// replace the barcodes of the item
+		if err := tx.Where("item_id = ?", item.ID).Delete(&models.ItemBarcode{}).Error; err != nil {
+			return err
+		}
+
+		// update the item data in the database
+		if err := tx.Omit("Barcodes").Save(&item).Error; err != nil {
+			return getItemSaveError(err)
+		}
+
+		if err := createItemBarcodes(tx, item.Barcodes); err != nil {
+			return err
+		}
+
+		if delta == 0 {
+			return nil
+		}
+
+		location, err := getDefaultLocation(tx, organizationID)
+		if err != nil {
+			return err
+		}
+
+		// the change of the quantity is recorded as the adjustment of the item
+		_, err = adjustStock(tx, models.StockMovement{
+			OrganizationID: organizationID,
+			ItemID:         item.ID,
+			LocationID:     location.ID,
+			Type:           models.MovementTypeAdjustment,
+			Quantity:       delta,
+			ReasonCode:     models.ReasonItemUpdate,
+			UserID:         userID,
+		})
+
+		if err != nil || delta > 0 {
+			return err
+		}
+
+		// the removed stock cannot be held by the reservations
+		return checkReservedStock(tx, item.ID)
+	})
+
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	// update the item in the search index
+	indexItem(item)
+
+	// return the updated item with its current quantities
+	return GetItemByID(organizationID, item.ID)
+}
+
+func DeleteItem(organizationID string, userID string, id string) error {
+	var item models.Item
+
+	// delete the item data with its barcodes and stock levels
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// lock the stock of the item
+		// if item is not found, return an error
+		lockedItem, err := lockItemStock(tx, organizationID, id)
+		if err != nil {
+			return err
+		}
+
+		item = lockedItem
+
+		// the variants of the product must be deleted first
+		if len(getItemVariants(tx, organizationID, item.ID)) > 0 {
+			return ErrItemHasVariants
+		}
+
+		// the item that is held by the active reservations cannot be deleted
+		if getReservedQuantity(tx, item.ID) > 0 {
+			return ErrStockReserved
+		}
+
+		// the stock in transit is received into the item, so the item cannot be deleted
+		if getInTransitQuantity(tx, item.ID) > 0 {
+			return ErrItemInTransit
+		}
+
+		// the remaining stock is written off before the stock levels are deleted
+		// so the movements of every location end with a zero balance
+		var stockLevels []models.StockLevel
+		if err := tx.Where("item_id = ? AND quantity <> 0", item.ID).Find(&stockLevels).Error; err != nil {
+			return err
+		}
+
+		for _, stockLevel := range stockLevels {
+			_, err := adjustStock(tx, models.StockMovement{
+				OrganizationID: organizationID,
+				ItemID:         item.ID,
+				LocationID:     stockLevel.LocationID,
+				Type:           models.MovementTypeAdjustment,
+				Quantity:       -stockLevel.Quantity,
+				ReasonCode:     models.ReasonItemDeleted,
+				UserID:         userID,
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("item_id = ?", item.ID).Delete(&models.ItemBarcode{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("item_id = ?", item.ID).Delete(&models.StockLevel{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("organization_id = ?", organizationID).Delete(&item).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// remove the item from the search index
+	removeIndexedItem(item)
+
+	// return nil
+	// this means the deletion is succeed
+	return nil
+}
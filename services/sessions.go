@@ -0,0 +1,96 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//Logout revokes the given access token and the session that issued it
+func Logout(metadata *utils.TokenMetadata) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		//add the access token into the denylist
+		var revokedToken models.RevokedToken = models.RevokedToken{
+			TokenID:   metadata.TokenID,
+			UserID:    metadata.UserID,
+			ExpiresAt: time.Unix(metadata.Expire, 0),
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revokedToken).Error; err != nil {
+			return err
+		}
+
+		//find the refresh token that is issued together with the access token
+		var refreshToken models.RefreshToken
+		result := tx.First(&refreshToken, "access_token_id = ?", metadata.TokenID)
+
+		//if the refresh token is not found, there is no session to revoke
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		//revoke every refresh token in the same family
+		return tx.Model(&models.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", refreshToken.FamilyID).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+//RevokeAllSessions revokes every access token and refresh token of the user
+func RevokeAllSessions(userID string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		return revokeAllSessions(tx, userID)
+	})
+}
+
+//revokeAllSessions revokes the sessions of the user inside the given transaction
+func revokeAllSessions(tx *gorm.DB, userID string) error {
+	var now time.Time = time.Now()
+
+	//find the refresh tokens whose access tokens are not expired yet
+	var refreshTokens []models.RefreshToken
+	if err := tx.Where("user_id = ? AND access_token_expires_at > ?", userID, now).Find(&refreshTokens).Error; err != nil {
+		return err
+	}
+
+	//add every access token that is still valid into the denylist
+	for _, refreshToken := range refreshTokens {
+		var revokedToken models.RevokedToken = models.RevokedToken{
+			TokenID:   refreshToken.AccessTokenID,
+			UserID:    userID,
+			ExpiresAt: refreshToken.AccessTokenExpiresAt,
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revokedToken).Error; err != nil {
+			return err
+		}
+	}
+
+	//revoke every refresh token of the user
+	return tx.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+}
+
+//IsTokenRevoked returns true if the token ID is in the denylist
+func IsTokenRevoked(tokenID string) bool {
+	var count int64
+	database.DB.Model(&models.RevokedToken{}).Where("token_id = ?", tokenID).Count(&count)
+	return count > 0
+}
+
+//PurgeExpiredRevokedTokens removes the denylist entries of expired tokens
+func PurgeExpiredRevokedTokens() {
+	result := database.DB.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{})
+
+	//if the purge is failed, print out the error
+	if result.Error != nil {
+		log.Println("error when purging revoked tokens:", result.Error)
+	}
+}
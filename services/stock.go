@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrInsufficientStock is returned when the stock of the location would become negative
+var ErrInsufficientStock = errors.New("insufficient stock at the location")
+
+//ErrQuantityByLocation is returned when the quantity of the item that is stocked in more than one location is updated
+//the stock of every location is changed with the movements of the item
+var ErrQuantityByLocation = errors.New("the item is stocked in more than one location, use the movements to change its quantity")
+
+//lockItemStock locks the item so its stock levels can be changed
+//the stock of the item that was recorded before the ledger existed is recorded as the opening balance
+func lockItemStock(tx *gorm.DB, organizationID string, itemID string) (models.Item, error) {
+	var item models.Item
+
+	if tx.Clauses(clause.Locking{Strength: "UPDATE"}).Limit(1).Find(&item, "organization_id = ? AND id = ?", organizationID, itemID).RowsAffected == 0 {
+		return models.Item{}, ErrItemNotFound
+	}
+
+	return item, recordOpeningBalance(tx, item)
+}
+
+//recordOpeningBalance records the stock of the item without movements as the opening balance
+//the item must be locked with lockItemStock first
+func recordOpeningBalance(tx *gorm.DB, item models.Item) error {
+	var count int64
+	tx.Model(&models.StockMovement{}).Where("item_id = ?", item.ID).Count(&count)
+
+	if count > 0 {
+		return nil
+	}
+
+	var stockLevels []models.StockLevel
+	tx.Where("item_id = ? AND quantity <> 0", item.ID).Find(&stockLevels)
+
+	//the quantity of the item that was stocked before the locations existed is moved into the default location
+	if len(stockLevels) == 0 && item.Quantity != 0 {
+		location, err := getDefaultLocation(tx, item.OrganizationID)
+		if err != nil {
+			return err
+		}
+
+		_, err = adjustStock(tx, models.StockMovement{
+			OrganizationID: item.OrganizationID,
+			ItemID:         item.ID,
+			LocationID:     location.ID,
+			Type:           models.MovementTypeAdjustment,
+			Quantity:       item.Quantity,
+			ReasonCode:     models.ReasonOpeningBalance,
+		})
+
+		return err
+	}
+
+	for _, stockLevel := range stockLevels {
+		if err := tx.Create(&models.StockMovement{
+			ID:             uuid.New().String(),
+			OrganizationID: item.OrganizationID,
+			ItemID:         item.ID,
+			LocationID:     stockLevel.LocationID,
+			Type:           models.MovementTypeAdjustment,
+			Quantity:       stockLevel.Quantity,
+			Balance:        stockLevel.Quantity,
+			ReasonCode:     models.ReasonOpeningBalance,
+			CreatedAt:      time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//RecordOpeningBalances records the opening balance of every item that was stocked before the ledger existed
+//it is run once when the application is started, so reading the stock never writes into the ledger
+func RecordOpeningBalances() {
+	var items []models.Item
+	database.DB.Where("NOT EXISTS (SELECT 1 FROM stock_movements WHERE stock_movements.item_id = items.id)").
+		Where("quantity <> 0 OR EXISTS (SELECT 1 FROM stock_levels WHERE stock_levels.item_id = items.id AND stock_levels.quantity <> 0)").
+		Find(&items)
+
+	for _, item := range items {
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			_, err := lockItemStock(tx, item.OrganizationID, item.ID)
+			return err
+		})
+
+		//if the opening balance is failed, print out the error
+		if err != nil {
+			log.Println("error when recording the opening balance of item", item.ID+":", err)
+		}
+	}
+}
+
+//adjustStock changes the stock of the item at the location by the quantity of the movement
+//the movement is recorded in the ledger, the item must be locked with lockItemStock first
+func adjustStock(tx *gorm.DB, movement models.StockMovement) (models.StockMovement, error) {
+	if movement.Quantity == 0 {
+		return models.StockMovement{}, nil
+	}
+
+	var stockLevel models.StockLevel
+	tx.Where("item_id = ? AND location_id = ?", movement.ItemID, movement.LocationID).Limit(1).Find(&stockLevel)
+
+	//if the stock would become negative, return an error
+	if stockLevel.Quantity+movement.Quantity < 0 {
+		return models.StockMovement{}, ErrInsufficientStock
+	}
+
+	if stockLevel.ID == "" {
+		stockLevel = models.StockLevel{
+			ID:             uuid.New().String(),
+			OrganizationID: movement.OrganizationID,
+			ItemID:         movement.ItemID,
+			LocationID:     movement.LocationID,
+		}
+	}
+
+	stockLevel.Quantity += movement.Quantity
+	stockLevel.UpdatedAt = time.Now()
+
+	if err := tx.Save(&stockLevel).Error; err != nil {
+		return models.StockMovement{}, err
+	}
+
+	//record the movement with the stock after the movement
+	movement.ID = uuid.New().String()
+	movement.Balance = stockLevel.Quantity
+	movement.CreatedAt = time.Now()
+
+	if err := tx.Create(&movement).Error; err != nil {
+		return models.StockMovement{}, err
+	}
+
+	return movement, refreshItemQuantity(tx, movement.ItemID)
+}
+
+//refreshItemQuantity stores the sum of the stock levels as the quantity of the item
+func refreshItemQuantity(tx *gorm.DB, itemID string) error {
+	return tx.Model(&models.Item{}).Where("id = ?", itemID).
+		Update("quantity", tx.Model(&models.StockLevel{}).Select("COALESCE(SUM(quantity), 0)").Where("item_id = ?", itemID)).
+		Error
+}
+
+//GetItemStock returns the stock of the item broken down by location
+func GetItemStock(organizationID string, itemID string) (models.ItemStock, error) {
+	//the stock is only read, the opening balances are recorded by RecordOpeningBalances and the stock changes
+	var item models.Item
+	if tenantDB(organizationID).Limit(1).Find(&item, "id = ?", itemID).RowsAffected == 0 {
+		return models.ItemStock{}, ErrItemNotFound
+	}
+
+	var locations []models.LocationStock = []models.LocationStock{}
+	database.DB.Model(&models.StockLevel{}).
+		Select("stock_levels.location_id, locations.code AS location_code, locations.name AS location_name, "+
+			"locations.warehouse_id, warehouses.code AS warehouse_code, warehouses.name AS warehouse_name, stock_levels.quantity").
+		Joins("JOIN locations ON locations.id = stock_levels.location_id").
+		Joins("JOIN warehouses ON warehouses.id = locations.warehouse_id").
+		Where("stock_levels.item_id = ? AND stock_levels.quantity <> 0", itemID).
+		Order("warehouses.code asc, locations.code asc").
+		Scan(&locations)
+
+	var stock models.ItemStock = models.ItemStock{
+		ItemID:     item.ID,
+		InTransit:  getInTransitQuantity(database.DB, itemID),
+		Locations:  locations,
+		Reconciled: true,
+	}
+
+	//reconcile the stock of every location against the sum of its movements
+	var ledger []models.LocationStock
+	database.DB.Model(&models.StockMovement{}).
+		Select("location_id, SUM(quantity) AS quantity").
+		Where("item_id = ?", itemID).
+		Group("location_id").
+		Scan(&ledger)
+
+	var ledgerQuantities map[string]int = map[string]int{}
+	for _, location := range ledger {
+		ledgerQuantities[location.LocationID] = location.Quantity
+	}
+
+	for i := range stock.Locations {
+		stock.Quantity += stock.Locations[i].Quantity
+		stock.Locations[i].LedgerQuantity = ledgerQuantities[stock.Locations[i].LocationID]
+		stock.Reconciled = stock.Reconciled && stock.Locations[i].LedgerQuantity == stock.Locations[i].Quantity
+
+		delete(ledgerQuantities, stock.Locations[i].LocationID)
+	}
+
+	//the ledger of the locations without stock must be zero as well
+	for _, quantity := range ledgerQuantities {
+		stock.Reconciled = stock.Reconciled && quantity == 0
+	}
+
+	return stock, nil
+}
+
+//SetItemStock sets the counted quantity of the item at the location
+//the difference is recorded as the adjustment of the stock count
+func SetItemStock(organizationID string, userID string, itemID string, locationID string, quantity int) (models.ItemStock, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockItemStock(tx, organizationID, itemID); err != nil {
+			return err
+		}
+
+		if _, err := getLocation(tx, organizationID, locationID); err != nil {
+			return err
+		}
+
+		var stockLevel models.StockLevel
+		tx.Where("item_id = ? AND location_id = ?", itemID, locationID).Limit(1).Find(&stockLevel)
+
+		var delta int = quantity - stockLevel.Quantity
+
+		_, err := adjustStock(tx, models.StockMovement{
+			OrganizationID: organizationID,
+			ItemID:         itemID,
+			LocationID:     locationID,
+			Type:           models.MovementTypeAdjustment,
+			Quantity:       delta,
+			ReasonCode:     models.ReasonStockCount,
+			UserID:         userID,
+		})
+
+		if err != nil || delta >= 0 {
+			return err
+		}
+
+		//the removed stock cannot be held by the reservations
+		return checkReservedStock(tx, itemID)
+	})
+
+	if err != nil {
+		return models.ItemStock{}, err
+	}
+
+	return GetItemStock(organizationID, itemID)
+}
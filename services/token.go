@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//issueTokenPair returns a new access token and refresh token for the user
+//the tokens are issued for the given organization
+//the refresh token is stored in the given token family
+func issueTokenPair(db *gorm.DB, user models.User, organizationID string, familyID string) (models.TokenPair, error) {
+	//make sure the signing keys are loaded
+	if err := ensureSigningKeys(); err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//generate the JWT token
+	accessToken, accessMetadata, err := utils.GeneralNewAccessToken(user, organizationID)
+
+	//if generation is failed, return the error
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//generate the refresh token
+	refreshToken, err := utils.GenerateRandomToken()
+
+	//if generation is failed, return the error
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//get the refresh token expire time from .env file
+	hoursCount, _ := strconv.Atoi(utils.GetValue("JWT_REFRESH_TOKEN_EXPIRE_HOURS_COUNT"))
+
+	//create a new refresh token object
+	//only the hash of the refresh token is stored into the database
+	var storedToken models.RefreshToken = models.RefreshToken{
+		ID:                   uuid.New().String(),
+		UserID:               user.ID,
+		FamilyID:             familyID,
+		OrganizationID:       organizationID,
+		TokenHash:            utils.HashToken(refreshToken),
+		AccessTokenID:        accessMetadata.TokenID,
+		AccessTokenExpiresAt: time.Unix(accessMetadata.Expire, 0),
+		ExpiresAt:            time.Now().Add(time.Hour * time.Duration(hoursCount)),
+	}
+
+	//insert the refresh token into the database
+	if err := db.Create(&storedToken).Error; err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//return the token pair
+	return models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+//RefreshToken returns a new token pair and rotates the given refresh token
+//if a refresh token is used twice, the whole token family is revoked
+func RefreshToken(refreshInput models.RefreshRequest) (models.TokenPair, error) {
+	//create a variable to store the new token pair
+	var tokenPair models.TokenPair
+
+	//create a variable to mark the reuse of a refresh token
+	var isReused bool
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		//create a variable called "storedToken"
+		var storedToken models.RefreshToken
+
+		//find the refresh token based on its hash
+		//the row is locked until the rotation is finished
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&storedToken, "token_hash = ?", utils.HashToken(refreshInput.RefreshToken))
+
+		//if the refresh token is not found, return the error
+		if result.RowsAffected == 0 {
+			return errors.New("invalid refresh token")
+		}
+
+		var now time.Time = time.Now()
+
+		//if the refresh token was already used or revoked
+		//revoke every refresh token in the same family
+		if storedToken.UsedAt != nil || storedToken.RevokedAt != nil {
+			isReused = true
+			return tx.Model(&models.RefreshToken{}).
+				Where("family_id = ? AND revoked_at IS NULL", storedToken.FamilyID).
+				Update("revoked_at", now).Error
+		}
+
+		//if the refresh token is expired, return the error
+		if now.After(storedToken.ExpiresAt) {
+			return errors.New("refresh token is expired")
+		}
+
+		//mark the refresh token as used
+		if err := tx.Model(&storedToken).Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		//find the owner of the refresh token
+		var user models.User
+		if tx.First(&user, "id = ?", storedToken.UserID).RowsAffected == 0 {
+			return errors.New("invalid refresh token")
+		}
+
+		//if the account is disabled, return the error
+		if user.DisabledAt != nil {
+			return ErrAccountDisabled
+		}
+
+		//if the user is removed from the organization
+		//the default organization of the user is selected instead
+		var organizationID string = storedToken.OrganizationID
+		if _, err := GetMembership(organizationID, user.ID); organizationID != "" && err != nil {
+			organizationID = getDefaultOrganizationID(user.ID)
+		}
+
+		//issue a new token pair in the same token family
+		var err error
+		tokenPair, err = issueTokenPair(tx, user, organizationID, storedToken.FamilyID)
+		return err
+	})
+
+	//if the refresh token was reused, return the error
+	//the revocation of the token family is already committed
+	if err == nil && isReused {
+		return models.TokenPair{}, errors.New("refresh token reuse detected")
+	}
+
+	//if the rotation is failed, return the error
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	//return the new token pair
+	return tokenPair, nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrTransferNotFound is returned when the transfer order is not found in the organization
+var ErrTransferNotFound = errors.New("transfer order not found")
+
+//ErrTransferStatus is returned when the action is not allowed in the status of the transfer order
+var ErrTransferStatus = errors.New("the transfer order cannot be changed in its current status")
+
+//ErrTransferLineNotFound is returned when the received item is not in the transfer order
+var ErrTransferLineNotFound = errors.New("the item is not in the transfer order")
+
+//ErrTransferOverReceipt is returned when more items are received than the quantity in transit
+var ErrTransferOverReceipt = errors.New("the received quantity is more than the quantity in transit")
+
+//ErrItemInTransit is returned when the item that is shipped but not received yet is deleted
+var ErrItemInTransit = errors.New("the item has stock in transit")
+
+//GetTransferOrders returns the transfer orders of the organization with the newest orders first
+func GetTransferOrders(organizationID string, transferQuery models.TransferQuery) []models.TransferOrder {
+	var query *gorm.DB = tenantDB(organizationID)
+
+	if transferQuery.Status != "" {
+		query = query.Where("status = ?", transferQuery.Status)
+	}
+
+	if transferQuery.ItemID != "" {
+		query = query.Where("id IN (SELECT transfer_order_id FROM transfer_lines WHERE item_id = ?)", transferQuery.ItemID)
+	}
+
+	var transfers []models.TransferOrder = []models.TransferOrder{}
+	query.Preload("Lines").Order("created_at desc").Find(&transfers)
+
+	for i := range transfers {
+		transfers[i].SetQuantities()
+	}
+
+	return transfers
+}
+
+//GetTransferOrderByID returns the transfer order of the organization with its lines
+func GetTransferOrderByID(organizationID string, id string) (models.TransferOrder, error) {
+	var transfer models.TransferOrder
+
+	if tenantDB(organizationID).Preload("Lines").Limit(1).Find(&transfer, "id = ?", id).RowsAffected == 0 {
+		return models.TransferOrder{}, ErrTransferNotFound
+	}
+
+	transfer.SetQuantities()
+
+	return transfer, nil
+}
+
+//CreateTransferOrder creates a new draft of the transfer order
+//the stock is not moved until the transfer order is shipped
+func CreateTransferOrder(organizationID string, userID string, transferInput models.TransferOrderRequest) (models.TransferOrder, error) {
+	var transfer models.TransferOrder = models.TransferOrder{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		FromLocationID: transferInput.FromLocationID,
+		ToLocationID:   transferInput.ToLocationID,
+		Status:         models.TransferStatusDraft,
+		Note:           transferInput.Note,
+		CreatedBy:      userID,
+	}
+
+	for _, line := range transferInput.Lines {
+		transfer.Lines = append(transfer.Lines, models.TransferLine{
+			ID:              uuid.New().String(),
+			TransferOrderID: transfer.ID,
+			ItemID:          line.ItemID,
+			Quantity:        line.Quantity,
+		})
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		//if the locations are not found, return an error
+		for _, locationID := range []string{transfer.FromLocationID, transfer.ToLocationID} {
+			if _, err := getLocation(tx, organizationID, locationID); err != nil {
+				return err
+			}
+		}
+
+		//if the items are not found, return an error
+		var itemIDs []string
+		for _, line := range transfer.Lines {
+			itemIDs = append(itemIDs, line.ItemID)
+		}
+
+		var count int64
+		tx.Model(&models.Item{}).Where("organization_id = ? AND id IN ?", organizationID, itemIDs).Count(&count)
+
+		if int(count) != len(itemIDs) {
+			return ErrItemNotFound
+		}
+
+		if err := tx.Omit("Lines").Create(&transfer).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&transfer.Lines).Error
+	})
+
+	if err != nil {
+		return models.TransferOrder{}, err
+	}
+
+	transfer.SetQuantities()
+
+	return transfer, nil
+}
+
+//lockTransferOrder locks the transfer order with its lines until its status is changed
+//the lines are sorted by the item so the items are always locked in the same order
+func lockTransferOrder(tx *gorm.DB, organizationID string, id string, status string) (models.TransferOrder, error) {
+	var transfer models.TransferOrder
+
+	if tx.Clauses(clause.Locking{Strength: "UPDATE"}).Limit(1).Find(&transfer, "organization_id = ? AND id = ?", organizationID, id).RowsAffected == 0 {
+		return models.TransferOrder{}, ErrTransferNotFound
+	}
+
+	if transfer.Status != status {
+		return models.TransferOrder{}, ErrTransferStatus
+	}
+
+	tx.Where("transfer_order_id = ?", id).Find(&transfer.Lines)
+
+	sort.Slice(transfer.Lines, func(i, j int) bool {
+		return transfer.Lines[i].ItemID < transfer.Lines[j].ItemID
+	})
+
+	return transfer, nil
+}
+
+//moveTransferStock moves the quantity of the item in or out of the location of the transfer order
+func moveTransferStock(tx *gorm.DB, transfer models.TransferOrder, userID string, itemID string, locationID string, quantity int, reasonCode string) error {
+	if _, err := lockItemStock(tx, transfer.OrganizationID, itemID); err != nil {
+		return err
+	}
+
+	_, err := adjustStock(tx, models.StockMovement{
+		OrganizationID: transfer.OrganizationID,
+		ItemID:         itemID,
+		LocationID:     locationID,
+		Type:           models.MovementTypeTransfer,
+		Quantity:       quantity,
+		ReasonCode:     reasonCode,
+		Reference:      transfer.ID,
+		UserID:         userID,
+	})
+
+	return err
+}
+
+//ShipTransferOrder removes the stock of the draft from its source location
+//the stock is in transit until it is received, nothing is moved if any item has insufficient stock
+func ShipTransferOrder(organizationID string, userID string, id string) (models.TransferOrder, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		transfer, err := lockTransferOrder(tx, organizationID, id, models.TransferStatusDraft)
+		if err != nil {
+			return err
+		}
+
+		for _, line := range transfer.Lines {
+			if err := moveTransferStock(tx, transfer, userID, line.ItemID, transfer.FromLocationID, -line.Quantity, models.ReasonTransferShipped); err != nil {
+				return err
+			}
+
+			//the shipped stock cannot be held by the reservations
+			if err := checkReservedStock(tx, line.ItemID); err != nil {
+				return err
+			}
+		}
+
+		var now time.Time = time.Now()
+		return tx.Model(&models.TransferOrder{}).Where("id = ?", transfer.ID).Updates(map[string]any{"status": models.TransferStatusShipped, "shipped_at": now}).Error
+	})
+
+	if err != nil {
+		return models.TransferOrder{}, err
+	}
+
+	return GetTransferOrderByID(organizationID, id)
+}
+
+//ReceiveTransferOrder adds the received stock into the destination location
+//the transfer is received when every item is received or the receipt is complete
+func ReceiveTransferOrder(organizationID string, userID string, id string, receiptInput models.TransferReceiptRequest) (models.TransferOrder, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		transfer, err := lockTransferOrder(tx, organizationID, id, models.TransferStatusShipped)
+		if err != nil {
+			return err
+		}
+
+		var received map[string]int = map[string]int{}
+		for _, line := range receiptInput.Lines {
+			received[line.ItemID] = line.Quantity
+		}
+
+		var isReceived bool = true
+
+		for i, line := range transfer.Lines {
+			var quantity int = received[line.ItemID]
+			delete(received, line.ItemID)
+
+			//if more items are received than the quantity in transit, return an error
+			if line.ReceivedQuantity+quantity > line.Quantity {
+				return ErrTransferOverReceipt
+			}
+
+			if quantity > 0 {
+				if err := moveTransferStock(tx, transfer, userID, line.ItemID, transfer.ToLocationID, quantity, models.ReasonTransferReceived); err != nil {
+					return err
+				}
+
+				transfer.Lines[i].ReceivedQuantity += quantity
+
+				if err := tx.Model(&transfer.Lines[i]).Update("received_quantity", transfer.Lines[i].ReceivedQuantity).Error; err != nil {
+					return err
+				}
+			}
+
+			isReceived = isReceived && transfer.Lines[i].ReceivedQuantity == line.Quantity
+		}
+
+		//if the received item is not in the transfer, return an error
+		if len(received) > 0 {
+			return ErrTransferLineNotFound
+		}
+
+		if !isReceived && !receiptInput.Complete {
+			return tx.Model(&models.TransferOrder{}).Where("id = ?", transfer.ID).Update("updated_at", time.Now()).Error
+		}
+
+		var now time.Time = time.Now()
+		return tx.Model(&models.TransferOrder{}).Where("id = ?", transfer.ID).Updates(map[string]any{"status": models.TransferStatusReceived, "received_at": now}).Error
+	})
+
+	if err != nil {
+		return models.TransferOrder{}, err
+	}
+
+	return GetTransferOrderByID(organizationID, id)
+}
+
+//CancelTransferOrder cancels the draft or the shipped transfer that is not received yet
+//the stock of the shipped transfer is returned into its source location
+func CancelTransferOrder(organizationID string, userID string, id string) (models.TransferOrder, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var transfer models.TransferOrder
+		if tx.Clauses(clause.Locking{Strength: "UPDATE"}).Limit(1).Find(&transfer, "organization_id = ? AND id = ?", organizationID, id).RowsAffected == 0 {
+			return ErrTransferNotFound
+		}
+
+		if transfer.Status == models.TransferStatusShipped {
+			shipped, err := lockTransferOrder(tx, organizationID, id, models.TransferStatusShipped)
+			if err != nil {
+				return err
+			}
+
+			for _, line := range shipped.Lines {
+				//the transfer that is partly received can only be completed
+				if line.ReceivedQuantity > 0 {
+					return ErrTransferStatus
+				}
+
+				if err := moveTransferStock(tx, shipped, userID, line.ItemID, shipped.FromLocationID, line.Quantity, models.ReasonTransferCancelled); err != nil {
+					return err
+				}
+			}
+		} else if transfer.Status != models.TransferStatusDraft {
+			return ErrTransferStatus
+		}
+
+		var now time.Time = time.Now()
+		return tx.Model(&models.TransferOrder{}).Where("id = ?", transfer.ID).Updates(map[string]any{"status": models.TransferStatusCancelled, "cancelled_at": now}).Error
+	})
+
+	if err != nil {
+		return models.TransferOrder{}, err
+	}
+
+	return GetTransferOrderByID(organizationID, id)
+}
+
+//getInTransitQuantity returns the quantity of the item that is shipped but not received yet
+func getInTransitQuantity(tx *gorm.DB, itemID string) int {
+	var quantity int
+
+	tx.Model(&models.TransferLine{}).
+		Select("COALESCE(SUM(transfer_lines.quantity - transfer_lines.received_quantity), 0)").
+		Joins("JOIN transfer_orders ON transfer_orders.id = transfer_lines.transfer_order_id").
+		Where("transfer_lines.item_id = ? AND transfer_orders.status = ?", itemID, models.TransferStatusShipped).
+		Scan(&quantity)
+
+	return quantity
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrInvalidUserToken is returned when the token is unknown, used or expired
+var ErrInvalidUserToken = errors.New("invalid or expired token")
+
+//issueUserToken returns a new single-use token for the user
+//the previous unused tokens with the same purpose are invalidated
+func issueUserToken(db *gorm.DB, userID string, purpose string, lifetime time.Duration) (string, error) {
+	var now time.Time = time.Now()
+
+	//invalidate the previous tokens with the same purpose
+	if err := db.Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", now).Error; err != nil {
+		return "", err
+	}
+
+	//generate a new random token
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	//create a new user token object
+	//only the hash of the token is stored into the database
+	var userToken models.UserToken = models.UserToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: now.Add(lifetime),
+	}
+
+	//insert the user token into the database
+	if err := db.Create(&userToken).Error; err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+//consumeUserToken marks the token as used and returns it
+//an error is returned if the token is unknown, used or expired
+func consumeUserToken(tx *gorm.DB, token string, purpose string) (models.UserToken, error) {
+	//create a variable called "userToken"
+	var userToken models.UserToken
+
+	//find the token based on its hash
+	//the row is locked so the token can only be used once
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&userToken, "token_hash = ? AND purpose = ?", utils.HashToken(token), purpose)
+
+	var now time.Time = time.Now()
+
+	//if the token is not valid anymore, return an error
+	if result.RowsAffected == 0 || userToken.UsedAt != nil || now.After(userToken.ExpiresAt) {
+		return models.UserToken{}, ErrInvalidUserToken
+	}
+
+	//mark the token as used
+	if err := tx.Model(&userToken).Update("used_at", now).Error; err != nil {
+		return models.UserToken{}, err
+	}
+
+	return userToken, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"gorm.io/gorm"
+)
+
+//GetUserByID returns the user data based on the given ID
+func GetUserByID(id string) (models.User, error) {
+	// create a variable to store user data
+	var user models.User
+
+	// get user data from the database by ID
+	result := database.DB.First(&user, "id = ?", id)
+
+	// if the user data is not found, return an error
+	if result.RowsAffected == 0 {
+		return models.User{}, ErrUserNotFound
+	}
+
+	// return the user data from the database
+	return user, nil
+}
+
+//ErrUserNotFound is returned when the user does not exist
+var ErrUserNotFound = errors.New("user not found")
+
+//ErrAccountDisabled is returned when the account is disabled by an admin
+var ErrAccountDisabled = errors.New("account is disabled")
+
+//GetUsers returns a page of users and the total number of matched users
+func GetUsers(userQuery models.UserQuery) ([]models.User, int64) {
+	// create a variable to store users data
+	var users []models.User = []models.User{}
+
+	var query *gorm.DB = database.DB.Model(&models.User{})
+
+	// filter the users by email
+	// the wildcards in the search are matched as normal characters
+	if userQuery.Search != "" {
+		query = query.Where("email LIKE ? ESCAPE '\\\\'", "%"+escapeLike(userQuery.Search)+"%")
+	}
+
+	// filter the users by role
+	if userQuery.Role != "" {
+		query = query.Where("role = ?", userQuery.Role)
+	}
+
+	// filter the users by status
+	switch userQuery.Status {
+	case "active":
+		query = query.Where("disabled_at IS NULL")
+	case "disabled":
+		query = query.Where("disabled_at IS NOT NULL")
+	}
+
+	// count the matched users
+	var total int64
+	query.Count(&total)
+
+	// get the requested page order by created_at
+	query.Order("created_at desc").
+		Offset((userQuery.Page - 1) * userQuery.PerPage).
+		Limit(userQuery.PerPage).
+		Find(&users)
+
+	return users, total
+}
+
+//likeEscaper escapes the wildcards of the LIKE pattern with a backslash
+var likeEscaper *strings.Replacer = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+
+//escapeLike returns the text that is matched literally inside the LIKE pattern
+func escapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
+
+//UpdateUser changes the role or the status of the user
+//the sessions of the user are revoked when the account is disabled
+func UpdateUser(id string, userInput models.UpdateUserRequest, currentUser models.User) (models.User, error) {
+	// get the user data by ID
+	user, err := GetUserByID(id)
+	if err != nil {
+		return models.User{}, ErrUserNotFound
+	}
+
+	// the admin cannot lock themself out
+	if user.ID == currentUser.ID {
+		return models.User{}, errors.New("you cannot change your own role or status")
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// update the role of the user
+		if userInput.Role != nil {
+			user.Role = *userInput.Role
+		}
+
+		// disable or enable the account
+		if userInput.Disabled != nil {
+			if *userInput.Disabled && user.DisabledAt == nil {
+				var now time.Time = time.Now()
+				user.DisabledAt = &now
+
+				// the disabled user is logged out everywhere
+				if err := revokeAllSessions(tx, user.ID); err != nil {
+					return err
+				}
+			} else if !*userInput.Disabled {
+				user.DisabledAt = nil
+			}
+		}
+
+		// only the role and the status are changed, the other columns keep their stored values
+		return tx.Model(&user).Updates(map[string]any{
+			"role":        user.Role,
+			"disabled_at": user.DisabledAt,
+		}).Error
+	})
+
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+//DeleteUser removes the user and everything that belongs to the user
+func DeleteUser(id string, currentUser models.User) error {
+	// get the user data by ID
+	user, err := GetUserByID(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	// the admin cannot delete themself
+	if user.ID == currentUser.ID {
+		return errors.New("you cannot delete your own account")
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		// revoke the tokens that are still valid
+		if err := revokeAllSessions(tx, user.ID); err != nil {
+			return err
+		}
+
+		// remove the credentials, the memberships and the identities of the user
+		for _, model := range []any{&models.RefreshToken{}, &models.APIKey{}, &models.UserToken{}, &models.RecoveryCode{}, &models.Membership{}, &models.Identity{}} {
+			if err := tx.Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// revoke the pending invitations that are sent by the user
+		if err := tx.Model(&models.Invitation{}).
+			Where("invited_by = ? AND accepted_at IS NULL AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		// remove the failed logins of the account
+		if err := tx.Delete(&models.LoginThrottle{}, "`key` = ?", accountThrottleKey(user.Email)).Error; err != nil {
+			return err
+		}
+
+		// delete the user data
+		return tx.Delete(&user).Error
+	})
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//ErrVariantOfVariant is returned when the variants are generated for a variant
+var ErrVariantOfVariant = errors.New("variants cannot be generated for a variant")
+
+//ErrVariantAxesMismatch is returned when the axes do not match the axes of the existing variants
+var ErrVariantAxesMismatch = errors.New("the axes must match the axes of the existing variants")
+
+//ErrTooManyVariants is returned when the product would have too many variants
+var ErrTooManyVariants = errors.New("the product would have too many variants")
+
+//ErrVariantSKUTooLong is returned when the SKU of the product with the axis values does not fit into the SKU column
+var ErrVariantSKUTooLong = errors.New("the sku of the variant is longer than 64 characters")
+
+//ErrItemHasVariants is returned when the product that still has variants is deleted
+var ErrItemHasVariants = errors.New("item still has variants")
+
+//maxSKULength is the size of the SKU column
+const maxSKULength = 64
+
+//getItemVariants returns the variants of the product
+func getItemVariants(tx *gorm.DB, organizationID string, parentID string) []models.Item {
+	var variants []models.Item = []models.Item{}
+	tx.Preload("Barcodes").Where("organization_id = ? AND parent_id = ?", organizationID, parentID).Order("sku asc").Find(&variants)
+	return variants
+}
+
+//GenerateVariants creates a variant for every combination of the axis values that does not exist yet
+//the new values are added into the axes of the product
+func GenerateVariants(organizationID string, id string, variantInput models.VariantMatrixRequest) (models.Item, error) {
+	var created []models.Item
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		//lock the product so the variants are only generated once
+		var parent models.Item
+		if tx.Clauses(clause.Locking{Strength: "UPDATE"}).Limit(1).Find(&parent, "organization_id = ? AND id = ?", organizationID, id).RowsAffected == 0 {
+			return ErrItemNotFound
+		}
+
+		if parent.ParentID != nil {
+			return ErrVariantOfVariant
+		}
+
+		var variants []models.Item = getItemVariants(tx, organizationID, id)
+
+		axes, err := mergeVariantAxes(parent.VariantAxes, variantInput.Axes, len(variants) > 0)
+		if err != nil {
+			return err
+		}
+
+		//skip the combinations that already have a variant
+		var existing map[string]bool = map[string]bool{}
+		for _, variant := range variants {
+			existing[getVariantKey(axes, variant.VariantOptions)] = true
+		}
+
+		var price int = parent.Price
+		if variantInput.Price != nil {
+			price = *variantInput.Price
+		}
+
+		for _, options := range getVariantCombinations(variantInput.Axes) {
+			if !existing[getVariantKey(axes, options)] {
+				created = append(created, newVariant(parent, axes, options, price))
+			}
+		}
+
+		if len(variants)+len(created) > models.MaxVariants {
+			return ErrTooManyVariants
+		}
+
+		for _, variant := range created {
+			if len(variant.SKU) > maxSKULength {
+				return ErrVariantSKUTooLong
+			}
+
+			//if the SKU is already used, return an error
+			if err := checkItemIdentifiers(tx, organizationID, variant.ID, variant.SKU, nil); err != nil {
+				return err
+			}
+
+			if err := tx.Omit("Barcodes").Create(&variant).Error; err != nil {
+				return getItemSaveError(err)
+			}
+		}
+
+		parent.VariantAxes = axes
+		parent.UpdatedAt = time.Now()
+
+		return tx.Omit("Barcodes").Save(&parent).Error
+	})
+
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	//add the new variants into the search index
+	for _, variant := range created {
+		indexItem(variant)
+	}
+
+	return GetItemByID(organizationID, id)
+}
+
+//mergeVariantAxes adds the values of the new axes into the axes of the product
+//the axes cannot be changed once the product has variants
+func mergeVariantAxes(axes []models.VariantAxis, newAxes []models.VariantAxis, hasVariants bool) ([]models.VariantAxis, error) {
+	if !hasVariants || len(axes) == 0 {
+		return newAxes, nil
+	}
+
+	if len(axes) != len(newAxes) {
+		return nil, ErrVariantAxesMismatch
+	}
+
+	var merged []models.VariantAxis = make([]models.VariantAxis, len(axes))
+
+	for i, axis := range axes {
+		var newAxis *models.VariantAxis
+		for j := range newAxes {
+			if newAxes[j].Name == axis.Name {
+				newAxis = &newAxes[j]
+			}
+		}
+
+		if newAxis == nil {
+			return nil, ErrVariantAxesMismatch
+		}
+
+		merged[i] = models.VariantAxis{Name: axis.Name, Values: append([]string{}, axis.Values...)}
+
+		for _, value := range newAxis.Values {
+			if !containsString(merged[i].Values, value) {
+				merged[i].Values = append(merged[i].Values, value)
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+//getVariantCombinations returns every combination of the axis values
+func getVariantCombinations(axes []models.VariantAxis) []map[string]string {
+	var combinations []map[string]string = []map[string]string{{}}
+
+	for _, axis := range axes {
+		var next []map[string]string
+
+		for _, combination := range combinations {
+			for _, value := range axis.Values {
+				var options map[string]string = map[string]string{axis.Name: value}
+				for name, option := range combination {
+					options[name] = option
+				}
+
+				next = append(next, options)
+			}
+		}
+
+		combinations = next
+	}
+
+	return combinations
+}
+
+//getVariantKey returns the axis values of the variant in the order of the axes
+func getVariantKey(axes []models.VariantAxis, options map[string]string) string {
+	var values []string
+	for _, axis := range axes {
+		values = append(values, options[axis.Name])
+	}
+
+	return strings.Join(values, "\x00")
+}
+
+//newVariant returns the variant of the product with the axis values
+//the variant copies the category, description, tags and attributes of the product
+func newVariant(parent models.Item, axes []models.VariantAxis, options map[string]string, price int) models.Item {
+	var values []string
+	var skuParts []string = []string{parent.SKU}
+
+	for _, axis := range axes {
+		values = append(values, options[axis.Name])
+		skuParts = append(skuParts, getSKUPart(options[axis.Name]))
+	}
+
+	return models.Item{
+		ID:             uuid.New().String(),
+		OrganizationID: parent.OrganizationID,
+		ParentID:       &parent.ID,
+		CategoryID:     parent.CategoryID,
+		SKU:            strings.Join(skuParts, "-"),
+		Name:           parent.Name + " - " + strings.Join(values, " / "),
+		Description:    parent.Description,
+		Price:          price,
+		Tags:           parent.Tags,
+		Attributes:     parent.Attributes,
+		VariantOptions: options,
+		CreatedAt:      time.Now(),
+	}
+}
+
+//getSKUPart returns the uppercase letters and numbers of the value to be used in the SKU
+func getSKUPart(value string) string {
+	var part strings.Builder
+
+	for _, char := range strings.ToUpper(value) {
+		if char < unicode.MaxASCII && (unicode.IsLetter(char) || unicode.IsDigit(char)) {
+			part.WriteRune(char)
+		}
+	}
+
+	return part.String()
+}
+
+//containsString returns true if the value is in the values
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
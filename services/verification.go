@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+	"inventory-project-testing/utils"
+
+	"gorm.io/gorm"
+)
+
+//SendVerificationEmail sends the email verification link to the user
+func SendVerificationEmail(user models.User) error {
+	//if the email is already verified, return an error
+	if user.EmailVerifiedAt != nil {
+		return errors.New("email is already verified")
+	}
+
+	//get the verification token expire time from .env file
+	hoursCount, _ := strconv.Atoi(utils.GetValue("EMAIL_VERIFICATION_EXPIRE_HOURS_COUNT"))
+
+	//create a new verification token
+	token, err := issueUserToken(database.DB, user.ID, models.TokenPurposeEmailVerification, time.Hour*time.Duration(hoursCount))
+	if err != nil {
+		return err
+	}
+
+	//send the verification token to the user
+	return getMailer().Send(utils.Mail{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: "Use the link below to verify your email. The link expires in " + strconv.Itoa(hoursCount) + " hours.\n\n" +
+			utils.GetValue("APP_URL") + "/api/v1/verify-email?token=" + token,
+	})
+}
+
+//VerifyEmail marks the email of the user as verified with the verification token
+func VerifyEmail(token string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		//use the verification token
+		userToken, err := consumeUserToken(tx, token, models.TokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		//mark the email of the user as verified
+		return tx.Model(&models.User{}).
+			Where("id = ? AND email_verified_at IS NULL", userToken.UserID).
+			Update("email_verified_at", time.Now()).Error
+	})
+}
+
+//IsEmailVerificationRequired returns true if unverified users are blocked
+func IsEmailVerificationRequired() bool {
+	required, _ := strconv.ParseBool(utils.GetValue("REQUIRE_EMAIL_VERIFICATION"))
+	return required
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"inventory-project-testing/database"
+	"inventory-project-testing/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//ErrWarehouseNotFound is returned when the warehouse is not found in the organization
+var ErrWarehouseNotFound = errors.New("warehouse not found")
+
+//ErrLocationNotFound is returned when the location is not found in the organization
+var ErrLocationNotFound = errors.New("location not found")
+
+//ErrWarehouseNotEmpty is returned when the warehouse that still has locations is deleted
+var ErrWarehouseNotEmpty = errors.New("warehouse still has locations")
+
+//ErrLocationNotEmpty is returned when the location that still has stock is deleted
+var ErrLocationNotEmpty = errors.New("location still has stock")
+
+//ErrDuplicateWarehouse is returned when the warehouse code is already used in the organization
+var ErrDuplicateWarehouse = errors.New("warehouse code is already used")
+
+//ErrDuplicateLocation is returned when the location code is already used in the warehouse
+var ErrDuplicateLocation = errors.New("location code is already used in the warehouse")
+
+//the warehouse and location that are created when the organization stocks its first item
+const (
+	defaultWarehouseCode = "MAIN"
+	defaultWarehouseName = "Main warehouse"
+	defaultLocationCode  = "DEFAULT"
+	defaultLocationName  = "Default location"
+)
+
+//GetWarehouses returns the warehouses of the organization
+func GetWarehouses(organizationID string) []models.Warehouse {
+	var warehouses []models.Warehouse = []models.Warehouse{}
+	tenantDB(organizationID).Order("code asc").Find(&warehouses)
+	return warehouses
+}
+
+//CreateWarehouse creates a new warehouse in the organization
+func CreateWarehouse(organizationID string, warehouseInput models.WarehouseRequest) (models.Warehouse, error) {
+	var warehouse models.Warehouse = models.Warehouse{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+	}
+
+	if err := saveWarehouse(database.DB, &warehouse, warehouseInput); err != nil {
+		return models.Warehouse{}, err
+	}
+
+	return warehouse, nil
+}
+
+//UpdateWarehouse updates the warehouse of the organization
+func UpdateWarehouse(organizationID string, id string, warehouseInput models.WarehouseRequest) (models.Warehouse, error) {
+	var warehouse models.Warehouse
+
+	if tenantDB(organizationID).Limit(1).Find(&warehouse, "id = ?", id).RowsAffected == 0 {
+		return models.Warehouse{}, ErrWarehouseNotFound
+	}
+
+	warehouse.UpdatedAt = time.Now()
+
+	if err := saveWarehouse(database.DB, &warehouse, warehouseInput); err != nil {
+		return models.Warehouse{}, err
+	}
+
+	return warehouse, nil
+}
+
+//saveWarehouse saves the warehouse if its code is not used by another warehouse
+func saveWarehouse(tx *gorm.DB, warehouse *models.Warehouse, warehouseInput models.WarehouseRequest) error {
+	var count int64
+	tx.Model(&models.Warehouse{}).Where("organization_id = ? AND code = ? AND id <> ?", warehouse.OrganizationID, warehouseInput.Code, warehouse.ID).Count(&count)
+
+	if count > 0 {
+		return ErrDuplicateWarehouse
+	}
+
+	warehouse.Code = warehouseInput.Code
+	warehouse.Name = warehouseInput.Name
+	warehouse.Address = warehouseInput.Address
+
+	return tx.Save(warehouse).Error
+}
+
+//DeleteWarehouse deletes the warehouse of the organization
+//the locations of the warehouse must be deleted first
+func DeleteWarehouse(organizationID string, id string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var warehouse models.Warehouse
+		if tx.Limit(1).Find(&warehouse, "organization_id = ? AND id = ?", organizationID, id).RowsAffected == 0 {
+			return ErrWarehouseNotFound
+		}
+
+		var count int64
+		tx.Model(&models.Location{}).Where("warehouse_id = ?", id).Count(&count)
+
+		if count > 0 {
+			return ErrWarehouseNotEmpty
+		}
+
+		return tx.Delete(&warehouse).Error
+	})
+}
+
+//GetLocations returns the locations of the warehouse
+func GetLocations(organizationID string, warehouseID string) ([]models.Location, error) {
+	var warehouse models.Warehouse
+	if tenantDB(organizationID).Limit(1).Find(&warehouse, "id = ?", warehouseID).RowsAffected == 0 {
+		return nil, ErrWarehouseNotFound
+	}
+
+	var locations []models.Location = []models.Location{}
+	tenantDB(organizationID).Where("warehouse_id = ?", warehouseID).Order("code asc").Find(&locations)
+
+	return locations, nil
+}
+
+//CreateLocation creates a new location in the warehouse
+func CreateLocation(organizationID string, warehouseID string, locationInput models.LocationRequest) (models.Location, error) {
+	var warehouse models.Warehouse
+	if tenantDB(organizationID).Limit(1).Find(&warehouse, "id = ?", warehouseID).RowsAffected == 0 {
+		return models.Location{}, ErrWarehouseNotFound
+	}
+
+	var location models.Location = models.Location{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		WarehouseID:    warehouseID,
+	}
+
+	if err := saveLocation(database.DB, &location, locationInput); err != nil {
+		return models.Location{}, err
+	}
+
+	return location, nil
+}
+
+//UpdateLocation updates the location of the warehouse
+func UpdateLocation(organizationID string, warehouseID string, id string, locationInput models.LocationRequest) (models.Location, error) {
+	var location models.Location
+
+	if tenantDB(organizationID).Limit(1).Find(&location, "warehouse_id = ? AND id = ?", warehouseID, id).RowsAffected == 0 {
+		return models.Location{}, ErrLocationNotFound
+	}
+
+	location.UpdatedAt = time.Now()
+
+	if err := saveLocation(database.DB, &location, locationInput); err != nil {
+		return models.Location{}, err
+	}
+
+	return location, nil
+}
+
+//saveLocation saves the location if its code is not used by another location of the warehouse
+func saveLocation(tx *gorm.DB, location *models.Location, locationInput models.LocationRequest) error {
+	var count int64
+	tx.Model(&models.Location{}).Where("warehouse_id = ? AND code = ? AND id <> ?", location.WarehouseID, locationInput.Code, location.ID).Count(&count)
+
+	if count > 0 {
+		return ErrDuplicateLocation
+	}
+
+	location.Code = locationInput.Code
+	location.Name = locationInput.Name
+
+	return tx.Save(location).Error
+}
+
+//DeleteLocation deletes the location of the warehouse
+//the location cannot be deleted if it still has stock
+func DeleteLocation(organizationID string, warehouseID string, id string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var location models.Location
+		if tx.Limit(1).Find(&location, "organization_id = ? AND warehouse_id = ? AND id = ?", organizationID, warehouseID, id).RowsAffected == 0 {
+			return ErrLocationNotFound
+		}
+
+		var count int64
+		tx.Model(&models.StockLevel{}).Where("location_id = ? AND quantity <> 0", id).Count(&count)
+
+		if count > 0 {
+			return ErrLocationNotEmpty
+		}
+
+		//delete the empty stock levels with the location
+		if err := tx.Where("location_id = ?", id).Delete(&models.StockLevel{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&location).Error
+	})
+}
+
+//getLocation returns the location of the organization
+func getLocation(tx *gorm.DB, organizationID string, id string) (models.Location, error) {
+	var location models.Location
+
+	if tx.Limit(1).Find(&location, "organization_id = ? AND id = ?", organizationID, id).RowsAffected == 0 {
+		return models.Location{}, ErrLocationNotFound
+	}
+
+	return location, nil
+}
+
+//getDefaultLocation returns the oldest location of the organization
+//the main warehouse with the default location is created if the organization has no location
+func getDefaultLocation(tx *gorm.DB, organizationID string) (models.Location, error) {
+	var location models.Location
+
+	if tx.Where("organization_id = ?", organizationID).Order("created_at asc").Limit(1).Find(&location).RowsAffected > 0 {
+		return location, nil
+	}
+
+	//lock the organization and check again so the default location is only created once
+	if err := lockOrganization(tx, organizationID); err != nil {
+		return models.Location{}, err
+	}
+
+	if tx.Where("organization_id = ?", organizationID).Order("created_at asc").Limit(1).Find(&location).RowsAffected > 0 {
+		return location, nil
+	}
+
+	var warehouse models.Warehouse = models.Warehouse{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		Code:           defaultWarehouseCode,
+		Name:           defaultWarehouseName,
+	}
+
+	if err := tx.Create(&warehouse).Error; err != nil {
+		return models.Location{}, err
+	}
+
+	location = models.Location{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		WarehouseID:    warehouse.ID,
+		Code:           defaultLocationCode,
+		Name:           defaultLocationName,
+	}
+
+	if err := tx.Create(&location).Error; err != nil {
+		return models.Location{}, err
+	}
+
+	return location, nil
+}
@@ -1,145 +1,474 @@
-package utils
-
-import (
-	"strconv"
-	"strings"
-	"time"
-
-	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v4"
-)
-
-type TokenMetadata struct {
-	Expire int64
-}
-
-/*helper to generate tokens for authentication purposes in */
-
-//GenerateNewAccessToken JWT token
-func GeneralNewAccessToken() (string, error) {
-	//get the JWT secret ke from .env file 
-	secret := GetValue("JWT_SECRET_KEY")
-
-	//get the JWT token expire time from .env file 
-	minutesCount,_ :=strconv.Atoi(GetValue("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT"))
-
-	//create a JWT claim object
-	claims := jwt.MapClaims{}
-
-	//add expiration time for the token 
-	claims["exp"] = time.Now().Add(time.Minute + time.Duration(minutesCount)).Unix()
-
-	//create a new JWT token with the JWT claim object
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	//convert the token in a string format
-	t, err := token.SignedString([]byte(secret))
-
-	//if conversion failed, return the error
-	if err != nil {
-		return "",err
-	}
-
-	//return the token
-	return t,nil
-}
-
-
-//create a helper function called ExtractTokenMetadata.
-func ExtractTokenMetadata(c *fiber.Ctx) (*TokenMetadata, error) {
-	//verify the token 
-	token, err := verifyToken(c)
-
-	//if verification is failed, return an error
-	if err != nil {
-		return nil, err
-	}
-
-	//get the token claim data
-	claims, ok := token.Claims.(jwt.MapClaims)
-
-	//if token claim data exists and token is valid 
-	if ok && token.Valid{
-		//set the token expiration date
-		expires := int64(claims["exp"].(float64))
-
-		//return the token metadata
-		return &TokenMetadata{
-			Expire: expires,
-
-		},nil
-	}
-
-	//return an error if token is invalid
-	return nil, err
-}
-
-//CheckToken returns token check result
-func CheckToken(c *fiber.Ctx) (bool, error){
-	//get the current time
-	now := time.Now().Unix()
-
-	//get the token claim data
-	claims, err:= ExtractTokenMetadata(c)
-
-	//if claim data is not found or invalid
-	//return false
-	if err != nil {
-		return false,fiber.ErrBadGateway
-	}
-
-	//get the  expiration time from the claim data
-	expires := claims.Expire
-
-	//if the token is expired
-	//return false
-	if now > expires {
-		return false, err
-	}
-
-	//return true, this means the token is valid
-	return true,nil
-}
-
-//extractToken returns token from the Authorization header
-func extractToken (c *fiber.Ctx) string {
-	//get the bearer token from the Authorization header
-	bearToken := c.Get("Authorization")
-
-	//get the JWT token from the beare 
-	onlyToken :=  strings.Split(bearToken, " ")
-	if len(onlyToken) == 2 {
-		//return the JWT token
-		return onlyToken[1]
-	}
-
-	//return empty if bearer token is empty
-	return ""
-}
-
-//veriyToken returns verification result
-func verifyToken(c *fiber.Ctx) (*jwt.Token, error){
-	//get the token from the bearer token 
-	tokenString := extractToken(c)
-
-	//verify the token with the JWT secret key 
-	token, err := jwt.Parse(tokenString, jwtKeyFunc)
-
-	//if verification is failed, return an error
-	if err != nil {
-		return nil, err
-	}
-
-	//return the valid token 
-	return token, nil
-}
-
-
-//jwtKeyFunc returns the JWT secret key
-//this function is used to verify the token
-func jwtKeyFunc(token *jwt.Token) (interface{}, error) {
-	return []byte(GetValue("JWT_SECRET_KEY")),nil
-}
-
-
-
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"inventory-project-testing/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+//TokenMetadata contains the claims of the JWT token
+type TokenMetadata struct {
+	UserID   string
+	Email    string
+	TokenID  string
+	// the organization that is selected for the token
+	OrganizationID string
+	Issuer   string
+	Audience string
+	IssuedAt int64
+	Expire   int64
+}
+
+/*helper to generate tokens for authentication purposes in */
+
+//GenerateNewAccessToken JWT token for the given user and organization
+//the metadata of the generated token is returned as well
+func GeneralNewAccessToken(user models.User, organizationID string) (string, *TokenMetadata, error) {
+	//get the active signing key from the keyring
+	key, err := getActiveKey()
+	if err != nil {
+		return "",nil,err
+	}
+
+	//get the JWT token expire time from .env file 
+	minutesCount,_ :=strconv.Atoi(GetValue("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT"))
+
+	//get the current time
+	now := time.Now()
+
+	//create the metadata of the token
+	metadata := &TokenMetadata{
+		UserID:   user.ID,
+		Email:    user.Email,
+		TokenID:  uuid.New().String(),
+		OrganizationID: organizationID,
+		Issuer:   GetValue("JWT_ISSUER"),
+		Audience: GetValue("JWT_AUDIENCE"),
+		IssuedAt: now.Unix(),
+		Expire:   now.Add(time.Minute * time.Duration(minutesCount)).Unix(),
+	}
+
+	//create a JWT claim object
+	claims := jwt.MapClaims{}
+
+	//add the identity of the user into the token
+	claims["sub"] = metadata.UserID
+	claims["email"] = metadata.Email
+
+	//add the organization of the user into the token
+	claims["tid"] = metadata.OrganizationID
+
+	//add the token ID, issuer and audience for the token
+	claims["jti"] = metadata.TokenID
+	claims["iss"] = metadata.Issuer
+	claims["aud"] = metadata.Audience
+
+	//add issued time and expiration time for the token 
+	claims["iat"] = metadata.IssuedAt
+	claims["exp"] = metadata.Expire
+
+	//create a new JWT token with the JWT claim object
+	//the ID of the signing key is added into the token header
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	//convert the token in a string format
+	t, err := token.SignedString(key.PrivateKey)
+
+	//if conversion failed, return the error
+	if err != nil {
+		return "",nil,err
+	}
+
+	//return the token
+	return t,metadata,nil
+}
+
+
+//GenerateMFAToken returns a short-lived token for the second step of the login
+//the token cannot be used as an access token because of its audience
+func GenerateMFAToken(user models.User) (string, error) {
+	//get the active signing key from the keyring
+	key, err := getActiveKey()
+	if err != nil {
+		return "", err
+	}
+
+	//get the MFA token expire time from .env file
+	minutesCount, _ := strconv.Atoi(GetValue("MFA_TOKEN_EXPIRE_MINUTES_COUNT"))
+
+	var now time.Time = time.Now()
+
+	//create a JWT claim object for the pending login
+	claims := jwt.MapClaims{
+		"sub": user.ID,
+		"jti": uuid.New().String(),
+		"iss": GetValue("JWT_ISSUER"),
+		"aud": getMFAAudience(),
+		"iat": now.Unix(),
+		"exp": now.Add(time.Minute * time.Duration(minutesCount)).Unix(),
+	}
+
+	//create a new JWT token with the JWT claim object
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	//convert the token in a string format
+	return token.SignedString(key.PrivateKey)
+}
+
+//ParseMFAToken returns the user ID from the MFA token
+func ParseMFAToken(tokenString string) (string, error) {
+	//verify the token with the public key from the keyring
+	token, err := jwt.Parse(tokenString, JWTKeyFunc)
+	if err != nil {
+		return "", err
+	}
+
+	//get the token claim data
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return "", errors.New("invalid mfa token")
+	}
+
+	//make sure the token is an MFA token issued by this application
+	if !claims.VerifyIssuer(GetValue("JWT_ISSUER"), true) || !claims.VerifyAudience(getMFAAudience(), true) {
+		return "", errors.New("invalid mfa token")
+	}
+
+	userID, _ := claims["sub"].(string)
+	return userID, nil
+}
+
+//getMFAAudience returns the audience of the MFA tokens
+func getMFAAudience() string {
+	return GetValue("JWT_AUDIENCE") + "/mfa"
+}
+
+//GenerateInvitationToken returns a signed token for the invitation
+//the token expires together with the invitation
+func GenerateInvitationToken(invitation models.Invitation) (string, error) {
+	//get the active signing key from the keyring
+	key, err := getActiveKey()
+	if err != nil {
+		return "", err
+	}
+
+	//create a JWT claim object for the invitation
+	claims := jwt.MapClaims{
+		"sub":   invitation.ID,
+		"email": invitation.Email,
+		"tid":   invitation.OrganizationID,
+		"iss":   GetValue("JWT_ISSUER"),
+		"aud":   getInvitationAudience(),
+		"iat":   invitation.CreatedAt.Unix(),
+		"exp":   invitation.ExpiresAt.Unix(),
+	}
+
+	//create a new JWT token with the JWT claim object
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	//convert the token in a string format
+	return token.SignedString(key.PrivateKey)
+}
+
+//ParseInvitationToken returns the invitation ID from the invitation token
+func ParseInvitationToken(tokenString string) (string, error) {
+	//verify the token with the public key from the keyring
+	token, err := jwt.Parse(tokenString, JWTKeyFunc)
+	if err != nil {
+		return "", errors.New("invalid invitation token")
+	}
+
+	//get the token claim data
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return "", errors.New("invalid invitation token")
+	}
+
+	//make sure the token is an invitation token issued by this application
+	if !claims.VerifyIssuer(GetValue("JWT_ISSUER"), true) || !claims.VerifyAudience(getInvitationAudience(), true) {
+		return "", errors.New("invalid invitation token")
+	}
+
+	invitationID, _ := claims["sub"].(string)
+	return invitationID, nil
+}
+
+//getInvitationAudience returns the audience of the invitation tokens
+func getInvitationAudience() string {
+	return GetValue("JWT_AUDIENCE") + "/invite"
+}
+
+//GenerateRandomToken returns a new random token
+//this token is used for refresh tokens and other secrets
+func GenerateRandomToken() (string, error) {
+	//create a random value for the token
+	var randomBytes []byte = make([]byte, 32)
+
+	//if random generation is failed, return the error
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	//return the token in a URL safe format
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+//HashToken returns the SHA-256 hash of the given token
+//only the hash of the token is stored in the database
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+//create a helper function called ExtractTokenMetadata.
+func ExtractTokenMetadata(c *fiber.Ctx) (*TokenMetadata, error) {
+	//verify the token 
+	token, err := verifyToken(c)
+
+	//if verification is failed, return an error
+	if err != nil {
+		return nil, err
+	}
+
+	//return the token metadata
+	return GetTokenMetadata(token)
+}
+
+//GetTokenMetadata returns the metadata of a verified token
+func GetTokenMetadata(token *jwt.Token) (*TokenMetadata, error) {
+	//get the token claim data
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	//return an error if token is invalid
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	//make sure the token is issued by this application
+	if !claims.VerifyIssuer(GetValue("JWT_ISSUER"), true) {
+		return nil, errors.New("invalid token issuer")
+	}
+
+	//make sure the token is issued for this application
+	if !claims.VerifyAudience(GetValue("JWT_AUDIENCE"), true) {
+		return nil, errors.New("invalid token audience")
+	}
+
+	//get the string claims from the token
+	userID, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	tokenID, _ := claims["jti"].(string)
+	organizationID, _ := claims["tid"].(string)
+	issuer, _ := claims["iss"].(string)
+	audience, _ := claims["aud"].(string)
+
+	//get the numeric claims from the token
+	issuedAt, _ := claims["iat"].(float64)
+	expires, _ := claims["exp"].(float64)
+
+	//return the token metadata
+	return &TokenMetadata{
+		UserID:   userID,
+		Email:    email,
+		TokenID:  tokenID,
+		OrganizationID: organizationID,
+		Issuer:   issuer,
+		Audience: audience,
+		IssuedAt: int64(issuedAt),
+		Expire:   int64(expires),
+	}, nil
+}
+
+//SetCurrentUser stores the authenticated user for the request
+func SetCurrentUser(c *fiber.Ctx, user models.User) {
+	c.Locals("user", user)
+}
+
+//SetCurrentTokenMetadata stores the metadata of the token used by the request
+func SetCurrentTokenMetadata(c *fiber.Ctx, metadata *TokenMetadata) {
+	c.Locals("token_metadata", metadata)
+}
+
+//GetCurrentTokenMetadata returns the metadata of the token used by the request
+func GetCurrentTokenMetadata(c *fiber.Ctx) (*TokenMetadata, error) {
+	//get the metadata that is stored by the authentication middleware
+	metadata, ok := c.Locals("token_metadata").(*TokenMetadata)
+
+	//if the metadata is not found, return an error
+	if !ok {
+		return nil, errors.New("user is not authenticated")
+	}
+
+	//return the token metadata
+	return metadata, nil
+}
+
+//SetCurrentAPIKey stores the API key used by the request
+func SetCurrentAPIKey(c *fiber.Ctx, apiKey models.APIKey) {
+	c.Locals("api_key", apiKey)
+}
+
+//GetCurrentAPIKey returns the API key used by the request
+//false is returned if the request is not authenticated with an API key
+func GetCurrentAPIKey(c *fiber.Ctx) (models.APIKey, bool) {
+	apiKey, ok := c.Locals("api_key").(models.APIKey)
+	return apiKey, ok
+}
+
+//SetCurrentMembership stores the membership of the organization selected by the request
+func SetCurrentMembership(c *fiber.Ctx, membership models.Membership) {
+	c.Locals("membership", membership)
+}
+
+//GetCurrentMembership returns the membership of the organization selected by the request
+func GetCurrentMembership(c *fiber.Ctx) (models.Membership, error) {
+	//get the membership that is stored by the authentication middleware
+	membership, ok := c.Locals("membership").(models.Membership)
+
+	//if the membership is not found, return an error
+	if !ok {
+		return models.Membership{}, errors.New("no organization is selected")
+	}
+
+	//return the membership
+	return membership, nil
+}
+
+//GetCurrentOrganizationID returns the ID of the organization selected by the request
+func GetCurrentOrganizationID(c *fiber.Ctx) (string, error) {
+	membership, err := GetCurrentMembership(c)
+	if err != nil {
+		return "", err
+	}
+
+	return membership.OrganizationID, nil
+}
+
+//GetCurrentUser returns the authenticated user for the request
+func GetCurrentUser(c *fiber.Ctx) (models.User, error) {
+	//get the user that is stored by the authentication middleware
+	user, ok := c.Locals("user").(models.User)
+
+	//if the user is not found, return an error
+	if !ok {
+		return models.User{}, errors.New("user is not authenticated")
+	}
+
+	//return the authenticated user
+	return user, nil
+}
+
+//HasPermission returns true if the current user is granted the permission
+//the scopes of the API key are checked as well if the API key is used
+func HasPermission(c *fiber.Ctx, permission string) bool {
+	//get the authenticated user
+	user, err := GetCurrentUser(c)
+	if err != nil {
+		return false
+	}
+
+	//the permissions inside the organization are granted by the role of the membership
+	var role string = user.Role
+	if models.IsTenantPermission(permission) {
+		membership, err := GetCurrentMembership(c)
+		if err != nil {
+			return false
+		}
+
+		role = membership.Role
+	}
+
+	//if the role of the user is not granted the permission
+	//return false
+	if !models.HasPermission(role, permission) {
+		return false
+	}
+
+	//if the scopes of the API key do not grant the permission
+	//return false
+	if apiKey, ok := GetCurrentAPIKey(c); ok && !apiKey.AllowsPermission(permission) {
+		return false
+	}
+
+	return true
+}
+
+//CheckToken returns token check result
+func CheckToken(c *fiber.Ctx) (bool, error){
+	//if the request is authenticated with an API key
+	//the key is already checked by the middleware
+	if _, ok := GetCurrentAPIKey(c); ok {
+		return true, nil
+	}
+
+	//get the current time
+	now := time.Now().Unix()
+
+	//get the token claim data
+	claims, err:= ExtractTokenMetadata(c)
+
+	//if claim data is not found or invalid
+	//return false
+	if err != nil {
+		return false,fiber.ErrBadGateway
+	}
+
+	//get the  expiration time from the claim data
+	expires := claims.Expire
+
+	//if the token is expired
+	//return false
+	if now > expires {
+		return false, err
+	}
+
+	//return true, this means the token is valid
+	return true,nil
+}
+
+//extractToken returns token from the Authorization header
+func extractToken (c *fiber.Ctx) string {
+	//get the bearer token from the Authorization header
+	bearToken := c.Get("Authorization")
+
+	//get the JWT token from the beare 
+	onlyToken :=  strings.Split(bearToken, " ")
+	if len(onlyToken) == 2 {
+		//return the JWT token
+		return onlyToken[1]
+	}
+
+	//return empty if bearer token is empty
+	return ""
+}
+
+//veriyToken returns verification result
+func verifyToken(c *fiber.Ctx) (*jwt.Token, error){
+	//get the token from the bearer token 
+	tokenString := extractToken(c)
+
+	//verify the token with the public key from the keyring
+	token, err := jwt.Parse(tokenString, JWTKeyFunc)
+
+	//if verification is failed, return an error
+	if err != nil {
+		return nil, err
+	}
+
+	//return the valid token 
+	return token, nil
+}
@@ -0,0 +1,21 @@
+package utils
+
+import "time"
+
+//RunEvery runs the job periodically with the given interval
+//this function blocks, so it should be started in a goroutine
+func RunEvery(interval time.Duration, job func()) {
+	//if the interval is not configured, the job is disabled
+	if interval <= 0 {
+		return
+	}
+
+	//create a ticker with the given interval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	//run the job every time the ticker ticks
+	for range ticker.C {
+		job()
+	}
+}
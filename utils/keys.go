@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"inventory-project-testing/models"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+//Key is a parsed signing key
+type Key struct {
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+//keyring stores the keys that are used to sign and verify the JWT tokens
+var keyring = struct {
+	sync.RWMutex
+	active *Key
+	keys   map[string]Key
+}{}
+
+//GenerateSigningKeyPEM returns a new key pair in the PEM format
+func GenerateSigningKeyPEM(algorithm string) (string, string, error) {
+	//create a variable to store the private key
+	var privateKey crypto.Signer
+	var err error
+
+	//generate the private key based on the algorithm
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case jwt.SigningMethodEdDSA.Alg():
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return "", "", errors.New("unsupported signing algorithm " + algorithm)
+	}
+
+	//if generation is failed, return the error
+	if err != nil {
+		return "", "", err
+	}
+
+	//encode the private key
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	//encode the public key
+	publicBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return "", "", err
+	}
+
+	//return the key pair in the PEM format
+	var privatePEM []byte = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes})
+	var publicPEM []byte = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes})
+	return string(privatePEM), string(publicPEM), nil
+}
+
+//ParseSigningKey returns the parsed key from the stored signing key
+func ParseSigningKey(signingKey models.SigningKey) (Key, error) {
+	//decode the private key
+	block, _ := pem.Decode([]byte(signingKey.PrivateKey))
+	if block == nil {
+		return Key{}, errors.New("invalid signing key " + signingKey.ID)
+	}
+
+	parsedKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return Key{}, err
+	}
+
+	//make sure the private key can be used to sign the tokens
+	privateKey, ok := parsedKey.(crypto.Signer)
+	if !ok {
+		return Key{}, errors.New("invalid signing key " + signingKey.ID)
+	}
+
+	//return the parsed key
+	return Key{
+		ID:         signingKey.ID,
+		Algorithm:  signingKey.Algorithm,
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.Public(),
+	}, nil
+}
+
+//SetSigningKeys replaces the keys in the keyring
+//the active key is used to sign the new tokens
+func SetSigningKeys(active Key, keys []Key) {
+	keyring.Lock()
+	defer keyring.Unlock()
+
+	keyring.active = &active
+	keyring.keys = map[string]Key{}
+	for _, key := range keys {
+		keyring.keys[key.ID] = key
+	}
+	keyring.keys[active.ID] = active
+}
+
+//HasSigningKey returns true if the keyring contains an active key
+func HasSigningKey() bool {
+	keyring.RLock()
+	defer keyring.RUnlock()
+
+	return keyring.active != nil
+}
+
+//HasVerificationKey returns true if the keyring contains the key with the given ID
+func HasVerificationKey(keyID string) bool {
+	keyring.RLock()
+	defer keyring.RUnlock()
+
+	_, ok := keyring.keys[keyID]
+	return ok
+}
+
+//getActiveKey returns the key that is used to sign the new tokens
+func getActiveKey() (Key, error) {
+	keyring.RLock()
+	defer keyring.RUnlock()
+
+	if keyring.active == nil {
+		return Key{}, errors.New("signing key is not loaded")
+	}
+
+	return *keyring.active, nil
+}
+
+//JWTKeyFunc returns the public key that verifies the token
+//the key is selected based on the "kid" header of the token
+func JWTKeyFunc(token *jwt.Token) (interface{}, error) {
+	keyring.RLock()
+	defer keyring.RUnlock()
+
+	//get the key ID from the token header
+	keyID, _ := token.Header["kid"].(string)
+
+	//find the key in the keyring
+	key, ok := keyring.keys[keyID]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+
+	//make sure the token is signed with the algorithm of the key
+	if token.Method.Alg() != key.Algorithm {
+		return nil, errors.New("unexpected signing method")
+	}
+
+	return key.PublicKey, nil
+}
+
+//GetJWKS returns the public keys of the keyring in the JWK format
+func GetJWKS() models.JSONWebKeySet {
+	keyring.RLock()
+	defer keyring.RUnlock()
+
+	var keySet models.JSONWebKeySet = models.JSONWebKeySet{Keys: []models.JSONWebKey{}}
+
+	for _, key := range keyring.keys {
+		var webKey models.JSONWebKey = models.JSONWebKey{
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+		}
+
+		//add the public key parameters based on the key type
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			webKey.KeyType = "RSA"
+			webKey.Modulus = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			webKey.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			webKey.KeyType = "OKP"
+			webKey.Curve = "Ed25519"
+			webKey.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			continue
+		}
+
+		keySet.Keys = append(keySet.Keys, webKey)
+	}
+
+	//sort the keys to keep the response stable
+	sort.Slice(keySet.Keys, func(i, j int) bool {
+		return keySet.Keys[i].KeyID < keySet.Keys[j].KeyID
+	})
+
+	return keySet
+}
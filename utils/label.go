@@ -0,0 +1,276 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"sync"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+//ErrLabelCodeTooLong is returned when the SKU does not fit into the barcode of the label
+var ErrLabelCodeTooLong = errors.New("the sku is too long to be printed as a barcode on the label")
+
+//Label is the content printed on the label of the item
+type Label struct {
+	Name  string
+	Price string
+	SKU   string
+	// the content of the QR code
+	URL string
+}
+
+//the size of the label image, the label is printed with 300 DPI
+const (
+	labelWidth   = 800
+	labelHeight  = 400
+	labelPadding = 24
+	labelDPI     = 300
+	labelQRSize  = 150
+	// the blank modules on both sides of the barcode
+	barcodeQuietZone = 10
+	// the module of the barcode must be at least 2 pixels wide to be scanned
+	barcodeMinModuleWidth = 2
+)
+
+//the size of the sheet and its labels in millimeters
+//the A4 sheet contains 3 columns and 8 rows of labels
+const (
+	sheetWidth   = 210.0
+	sheetHeight  = 297.0
+	sheetColumns = 3
+	sheetRows    = 8
+	sheetMargin  = 2.0
+)
+
+//labelFaces are the fonts of the label text
+var (
+	labelFaces     map[string]font.Face
+	labelFacesErr  error
+	labelFacesOnce sync.Once
+)
+
+//getLabelFaces returns the fonts of the label text
+//the fonts are parsed when they are used for the first time
+func getLabelFaces() (map[string]font.Face, error) {
+	labelFacesOnce.Do(func() {
+		var faces map[string]font.Face = map[string]font.Face{}
+
+		for name, face := range map[string]struct {
+			data []byte
+			size float64
+		}{
+			"name":  {gobold.TTF, 44},
+			"price": {goregular.TTF, 36},
+			"sku":   {goregular.TTF, 28},
+		} {
+			parsedFont, err := opentype.Parse(face.data)
+			if err != nil {
+				labelFacesErr = err
+				return
+			}
+
+			faces[name], err = opentype.NewFace(parsedFont, &opentype.FaceOptions{Size: face.size, DPI: 72, Hinting: font.HintingFull})
+			if err != nil {
+				labelFacesErr = err
+				return
+			}
+		}
+
+		labelFaces = faces
+	})
+
+	return labelFaces, labelFacesErr
+}
+
+//RenderLabelImage draws the label with the name, price, SKU, Code 128 barcode and QR code
+func RenderLabelImage(label Label) (image.Image, error) {
+	faces, err := getLabelFaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var canvas *image.Gray = image.NewGray(image.Rect(0, 0, labelWidth, labelHeight))
+	draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+
+	//draw the QR code in the top right corner
+	qrCode, err := qr.Encode(label.URL, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+
+	qrCode, err = barcode.Scale(qrCode, labelQRSize, labelQRSize)
+	if err != nil {
+		return nil, err
+	}
+
+	draw.Draw(canvas, image.Rect(labelWidth-labelPadding-labelQRSize, labelPadding, labelWidth-labelPadding, labelPadding+labelQRSize), qrCode, image.Point{}, draw.Src)
+
+	//draw the text next to the QR code
+	var textWidth int = labelWidth - labelQRSize - labelPadding*3
+
+	drawLabelText(canvas, faces["name"], label.Name, labelPadding, 68, textWidth)
+	drawLabelText(canvas, faces["price"], label.Price, labelPadding, 120, textWidth)
+	drawLabelText(canvas, faces["sku"], label.SKU, labelPadding, 165, textWidth)
+
+	//draw the barcode of the SKU below the text
+	barcodeImage, err := renderLabelBarcode(label.SKU, labelWidth-labelPadding*2, labelHeight-labelPadding-200)
+	if err != nil {
+		return nil, err
+	}
+
+	var barcodeX int = (labelWidth - barcodeImage.Bounds().Dx()) / 2
+	draw.Draw(canvas, image.Rect(barcodeX, 200, barcodeX+barcodeImage.Bounds().Dx(), labelHeight-labelPadding), barcodeImage, image.Point{}, draw.Src)
+
+	return canvas, nil
+}
+
+//renderLabelBarcode returns the Code 128 barcode of the code that fits into the width
+//the modules of the barcode are scaled with a whole number to keep the barcode readable
+func renderLabelBarcode(code string, width int, height int) (image.Image, error) {
+	barcodeCode, err := code128.Encode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules int = barcodeCode.Bounds().Dx()
+	var moduleWidth int = width / (modules + barcodeQuietZone*2)
+
+	if moduleWidth < barcodeMinModuleWidth {
+		return nil, ErrLabelCodeTooLong
+	}
+
+	return barcode.Scale(barcodeCode, modules*moduleWidth, height)
+}
+
+//drawLabelText draws the text at the baseline
+//the text is shortened with an ellipsis if it is wider than the maximum width
+func drawLabelText(canvas draw.Image, face font.Face, text string, x int, y int, maxWidth int) {
+	var drawer *font.Drawer = &font.Drawer{
+		Dst:  canvas,
+		Src:  image.NewUniform(color.Black),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+
+	if drawer.MeasureString(text).Ceil() > maxWidth {
+		var runes []rune = []rune(text)
+		for len(runes) > 0 && drawer.MeasureString(string(runes)+"…").Ceil() > maxWidth {
+			runes = runes[:len(runes)-1]
+		}
+
+		text = string(runes) + "…"
+	}
+
+	drawer.DrawString(text)
+}
+
+//RenderLabelPNG returns the label in the PNG format
+func RenderLabelPNG(label Label) ([]byte, error) {
+	labelImage, err := RenderLabelImage(label)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, labelImage); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+//RenderLabelPDF returns the labels in the PDF format with one label on every page
+//the size of the page is the size of the label
+func RenderLabelPDF(labels []Label) ([]byte, error) {
+	var width, height float64 = pixelsToMillimeters(labelWidth), pixelsToMillimeters(labelHeight)
+
+	var pdf *fpdf.Fpdf = fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: width, Ht: height},
+	})
+
+	return renderLabelPages(pdf, labels, func(index int) (float64, float64, float64, float64) {
+		//every label is printed on a new page
+		pdf.AddPage()
+		return 0, 0, width, height
+	})
+}
+
+//RenderLabelSheetPDF returns the labels in the PDF format on A4 sheets
+//every sheet contains 3 columns and 8 rows of labels
+func RenderLabelSheetPDF(labels []Label) ([]byte, error) {
+	var pdf *fpdf.Fpdf = fpdf.New("P", "mm", "A4", "")
+
+	var cellWidth float64 = sheetWidth / sheetColumns
+	var cellHeight float64 = sheetHeight / sheetRows
+
+	//fit the label into the cell without changing the ratio of the label
+	var width float64 = cellWidth - sheetMargin*2
+	var height float64 = width * labelHeight / labelWidth
+
+	if height > cellHeight-sheetMargin*2 {
+		height = cellHeight - sheetMargin*2
+		width = height * labelWidth / labelHeight
+	}
+
+	return renderLabelPages(pdf, labels, func(index int) (float64, float64, float64, float64) {
+		//start a new sheet if the previous sheet is full
+		var position int = index % (sheetColumns * sheetRows)
+		if position == 0 {
+			pdf.AddPage()
+		}
+
+		var column, row int = position % sheetColumns, position / sheetColumns
+
+		return float64(column)*cellWidth + (cellWidth-width)/2, float64(row)*cellHeight + (cellHeight-height)/2, width, height
+	})
+}
+
+//renderLabelPages draws the labels into the PDF at the positions returned by the placement
+func renderLabelPages(pdf *fpdf.Fpdf, labels []Label, place func(index int) (float64, float64, float64, float64)) ([]byte, error) {
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+
+	for index, label := range labels {
+		labelPNG, err := RenderLabelPNG(label)
+		if err != nil {
+			return nil, err
+		}
+
+		//register the image of the label and draw it at its position
+		var name string = "label-" + strconv.Itoa(index)
+		var options fpdf.ImageOptions = fpdf.ImageOptions{ImageType: "PNG"}
+
+		pdf.RegisterImageOptionsReader(name, options, bytes.NewReader(labelPNG))
+
+		x, y, width, height := place(index)
+		pdf.ImageOptions(name, x, y, width, height, false, options, 0, "")
+	}
+
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+//pixelsToMillimeters returns the printed size of the label pixels in millimeters
+func pixelsToMillimeters(pixels int) float64 {
+	return float64(pixels) * 25.4 / labelDPI
+}
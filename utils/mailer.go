@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//Mail is an email that is sent by the application
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+//Mailer delivers the emails of the application
+type Mailer interface {
+	Send(mail Mail) error
+}
+
+//SMTPMailer delivers the emails with an SMTP server
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+//Send delivers the email with the SMTP server
+func (mailer SMTPMailer) Send(mail Mail) error {
+	//create the authentication if the credentials are provided
+	var auth smtp.Auth
+	if mailer.Username != "" {
+		auth = smtp.PlainAuth("", mailer.Username, mailer.Password, mailer.Host)
+	}
+
+	//create the email message
+	var message string = fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		mailer.From, mail.To, mail.Subject, mail.Body,
+	)
+
+	//send the email
+	return smtp.SendMail(mailer.Host+":"+mailer.Port, auth, mailer.From, []string{mail.To}, []byte(message))
+}
+
+//FileMailer writes the emails into a directory and the log
+//this mailer is used for local development and tests
+type FileMailer struct {
+	Directory string
+}
+
+//Send writes the email into the directory and the log
+func (mailer FileMailer) Send(mail Mail) error {
+	//print out the email
+	log.Printf("Mail to %s: %s\n%s\n", mail.To, mail.Subject, mail.Body)
+
+	//if the directory is not configured, the email is only logged
+	if mailer.Directory == "" {
+		return nil
+	}
+
+	//create the directory if it does not exist
+	if err := os.MkdirAll(mailer.Directory, 0o755); err != nil {
+		return err
+	}
+
+	//write the email into a new file
+	var fileName string = fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.ReplaceAll(mail.To, "@", "_at_"))
+	var content string = fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", mail.To, mail.Subject, mail.Body)
+
+	return os.WriteFile(filepath.Join(mailer.Directory, fileName), []byte(content), 0o644)
+}
+
+//NewMailer returns the mailer based on the configuration in the .env file
+func NewMailer() Mailer {
+	//use the SMTP server if it is configured
+	if GetValue("MAIL_DRIVER") == "smtp" {
+		return SMTPMailer{
+			Host:     GetValue("SMTP_HOST"),
+			Port:     GetValue("SMTP_PORT"),
+			Username: GetValue("SMTP_USERNAME"),
+			Password: GetValue("SMTP_PASSWORD"),
+			From:     GetValue("MAIL_FROM"),
+		}
+	}
+
+	//otherwise, write the emails into files
+	return FileMailer{Directory: GetValue("MAIL_FILE_DIRECTORY")}
+}
@@ -0,0 +1,262 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"inventory-project-testing/models"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+//OIDCProvider is an OpenID Connect provider used for the authorization code flow
+type OIDCProvider struct {
+	Issuer                string
+	ClientID              string
+	ClientSecret          string
+	RedirectURL           string
+	Scopes                []string
+	AuthorizationEndpoint string
+	TokenEndpoint         string
+	JWKSURI               string
+	// the HTTP client used to call the provider
+	Client *http.Client
+}
+
+//OIDCClaims contains the identity claims from the ID token
+type OIDCClaims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+//oidcDiscovery is the metadata document of the provider
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+//DiscoverOIDCProvider returns the provider configured by its discovery document
+func DiscoverOIDCProvider(issuer string, clientID string, clientSecret string, redirectURL string, scopes []string) (*OIDCProvider, error) {
+	var client *http.Client = &http.Client{Timeout: 10 * time.Second}
+
+	//get the discovery document of the provider
+	var discovery oidcDiscovery
+	if err := getJSON(client, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+
+	//the issuer in the document must be the configured issuer
+	if discovery.Issuer != issuer {
+		return nil, errors.New("oidc issuer does not match the discovery document")
+	}
+
+	return &OIDCProvider{
+		Issuer:                discovery.Issuer,
+		ClientID:              clientID,
+		ClientSecret:          clientSecret,
+		RedirectURL:           redirectURL,
+		Scopes:                scopes,
+		AuthorizationEndpoint: discovery.AuthorizationEndpoint,
+		TokenEndpoint:         discovery.TokenEndpoint,
+		JWKSURI:               discovery.JWKSURI,
+		Client:                client,
+	}, nil
+}
+
+//GeneratePKCE returns a new PKCE code verifier and its S256 code challenge
+func GeneratePKCE() (string, string, error) {
+	verifier, err := GenerateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	return verifier, GetPKCEChallenge(verifier), nil
+}
+
+//GetPKCEChallenge returns the S256 code challenge of the code verifier
+func GetPKCEChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+//AuthCodeURL returns the URL that starts the login at the provider
+func (provider *OIDCProvider) AuthCodeURL(state string, nonce string, codeChallenge string) string {
+	var query url.Values = url.Values{
+		"response_type":         {"code"},
+		"client_id":             {provider.ClientID},
+		"redirect_uri":          {provider.RedirectURL},
+		"scope":                 {strings.Join(provider.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	//keep the query of the endpoint if there is any
+	var separator string = "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return provider.AuthorizationEndpoint + separator + query.Encode()
+}
+
+//Exchange returns the ID token for the authorization code
+func (provider *OIDCProvider) Exchange(code string, codeVerifier string) (string, error) {
+	var form url.Values = url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {provider.RedirectURL},
+		"client_id":     {provider.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+
+	//the client secret is optional for public clients
+	if provider.ClientSecret != "" {
+		form.Set("client_secret", provider.ClientSecret)
+	}
+
+	//send the token request to the provider
+	response, err := provider.Client.PostForm(provider.TokenEndpoint, form)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oidc token request failed with status %d", response.StatusCode)
+	}
+
+	var tokenResponse struct {
+		IDToken string `json:"id_token"`
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return "", err
+	}
+
+	if tokenResponse.IDToken == "" {
+		return "", errors.New("oidc token response does not contain an id token")
+	}
+
+	return tokenResponse.IDToken, nil
+}
+
+//VerifyIDToken verifies the ID token and returns its identity claims
+//the nonce must be the nonce sent with the authorization request
+func (provider *OIDCProvider) VerifyIDToken(idToken string, nonce string) (OIDCClaims, error) {
+	//get the public keys of the provider
+	var keySet models.JSONWebKeySet
+	if err := getJSON(provider.Client, provider.JWKSURI, &keySet); err != nil {
+		return OIDCClaims{}, err
+	}
+
+	//verify the signature with the key from the header
+	token, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+
+		for _, webKey := range keySet.Keys {
+			if webKey.KeyID == keyID || keyID == "" {
+				return parseJSONWebKey(webKey)
+			}
+		}
+
+		return nil, errors.New("unknown oidc signing key")
+	}, jwt.WithValidMethods([]string{"RS256", "EdDSA"}))
+
+	if err != nil {
+		return OIDCClaims{}, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return OIDCClaims{}, errors.New("invalid id token")
+	}
+
+	//make sure the token is issued by the provider for this client
+	if !claims.VerifyIssuer(provider.Issuer, true) || !claims.VerifyAudience(provider.ClientID, true) {
+		return OIDCClaims{}, errors.New("invalid id token issuer or audience")
+	}
+
+	//make sure the token belongs to this login
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return OIDCClaims{}, errors.New("invalid id token nonce")
+	}
+
+	subject, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	emailVerified, _ := claims["email_verified"].(bool)
+	name, _ := claims["name"].(string)
+
+	if subject == "" {
+		return OIDCClaims{}, errors.New("id token does not contain a subject")
+	}
+
+	return OIDCClaims{
+		Issuer:        provider.Issuer,
+		Subject:       subject,
+		Email:         email,
+		EmailVerified: emailVerified,
+		Name:          name,
+	}, nil
+}
+
+//parseJSONWebKey returns the public key of the JWK
+func parseJSONWebKey(webKey models.JSONWebKey) (interface{}, error) {
+	switch webKey.KeyType {
+	case "RSA":
+		modulus, err := base64.RawURLEncoding.DecodeString(webKey.Modulus)
+		if err != nil {
+			return nil, err
+		}
+
+		exponent, err := base64.RawURLEncoding.DecodeString(webKey.Exponent)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}, nil
+	case "OKP":
+		publicKey, err := base64.RawURLEncoding.DecodeString(webKey.X)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid oidc signing key")
+		}
+
+		return ed25519.PublicKey(publicKey), nil
+	default:
+		return nil, errors.New("unsupported oidc signing key")
+	}
+}
+
+//getJSON decodes the JSON response of the URL
+func getJSON(client *http.Client, url string, value any) error {
+	response, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed with status %d", url, response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(value)
+}
@@ -0,0 +1,429 @@
+package utils
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//SearchDocument is a document stored in the search index
+//the fields are indexed with the weight of the field
+type SearchDocument struct {
+	ID     string
+	Fields map[string]string
+}
+
+//SearchHit is a document matched by the search
+type SearchHit struct {
+	ID         string
+	Score      float64
+	Highlights map[string]string
+}
+
+//SearchSuggestion is a completion of the prefix
+type SearchSuggestion struct {
+	ID   string
+	Text string
+}
+
+//the weights of the term matches
+const (
+	exactMatchWeight  = 1.0
+	prefixMatchWeight = 0.7
+	fuzzyMatchWeight  = 0.5
+)
+
+//SearchIndex is an in-memory inverted index with typo tolerance
+type SearchIndex struct {
+	mutex        sync.RWMutex
+	fieldWeights map[string]float64
+	documents    map[string]SearchDocument
+	// the postings contain the number of occurrences of the term per document and field
+	postings map[string]map[string]map[string]int
+	// the sorted terms are used for the prefix search
+	sortedTerms []string
+	sorted      bool
+}
+
+//NewSearchIndex returns an empty search index
+//the fields that are not in the weights are indexed with the weight 1
+func NewSearchIndex(fieldWeights map[string]float64) *SearchIndex {
+	return &SearchIndex{
+		fieldWeights: fieldWeights,
+		documents:    map[string]SearchDocument{},
+		postings:     map[string]map[string]map[string]int{},
+	}
+}
+
+//Index adds or replaces the document in the index
+func (index *SearchIndex) Index(document SearchDocument) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	//remove the old version of the document
+	index.remove(document.ID)
+
+	index.documents[document.ID] = document
+
+	//add every term of the fields into the postings
+	for field, text := range document.Fields {
+		for _, term := range Tokenize(text) {
+			if index.postings[term] == nil {
+				index.postings[term] = map[string]map[string]int{}
+				index.sorted = false
+			}
+
+			if index.postings[term][document.ID] == nil {
+				index.postings[term][document.ID] = map[string]int{}
+			}
+
+			index.postings[term][document.ID][field]++
+		}
+	}
+}
+
+//Remove deletes the document from the index
+func (index *SearchIndex) Remove(id string) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.remove(id)
+}
+
+//remove deletes the document, the lock must be held by the caller
+func (index *SearchIndex) remove(id string) {
+	document, ok := index.documents[id]
+	if !ok {
+		return
+	}
+
+	for _, text := range document.Fields {
+		for _, term := range Tokenize(text) {
+			delete(index.postings[term], id)
+
+			if len(index.postings[term]) == 0 {
+				delete(index.postings, term)
+				index.sorted = false
+			}
+		}
+	}
+
+	delete(index.documents, id)
+}
+
+//Len returns the number of documents in the index
+func (index *SearchIndex) Len() int {
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+
+	return len(index.documents)
+}
+
+//Search returns the documents that match every term of the query
+//the documents are ranked by the weight of the matched fields and terms
+func (index *SearchIndex) Search(query string, limit int) []SearchHit {
+	var queryTerms []string = Tokenize(query)
+	if len(queryTerms) == 0 {
+		return []SearchHit{}
+	}
+
+	index.prepareTerms()
+
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+
+	var scores map[string]float64
+	var matchedTerms map[string]bool = map[string]bool{}
+
+	for _, queryTerm := range queryTerms {
+		//score the documents that match the query term
+		var termScores map[string]float64 = map[string]float64{}
+
+		for term, weight := range index.matchTerm(queryTerm) {
+			//skip the term if it is removed after the terms are sorted
+			if len(index.postings[term]) == 0 {
+				continue
+			}
+
+			matchedTerms[term] = true
+
+			//the rare terms are ranked higher than the common terms
+			var idf float64 = math.Log(1 + float64(len(index.documents))/float64(len(index.postings[term])))
+
+			for id, fields := range index.postings[term] {
+				var score float64
+				for field, count := range fields {
+					score += index.getFieldWeight(field) * (1 + math.Log(float64(count)))
+				}
+
+				termScores[id] = math.Max(termScores[id], weight*idf*score)
+			}
+		}
+
+		//keep the documents that match every query term
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+
+		for id := range scores {
+			if termScore, ok := termScores[id]; ok {
+				scores[id] += termScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	var hits []SearchHit = []SearchHit{}
+	for id, score := range scores {
+		hits = append(hits, SearchHit{ID: id, Score: score})
+	}
+
+	//sort the documents by the score
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+
+		return hits[i].ID < hits[j].ID
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	//highlight the matched terms in the fields of the documents
+	for i := range hits {
+		hits[i].Highlights = map[string]string{}
+
+		for field, text := range index.documents[hits[i].ID].Fields {
+			if highlighted, ok := Highlight(text, matchedTerms); ok {
+				hits[i].Highlights[field] = highlighted
+			}
+		}
+	}
+
+	return hits
+}
+
+//Suggest returns the completions of the prefix from the field
+//the last word of the prefix can be incomplete
+func (index *SearchIndex) Suggest(prefix string, field string, limit int) []SearchSuggestion {
+	var prefixTerms []string = Tokenize(prefix)
+	if len(prefixTerms) == 0 {
+		return []SearchSuggestion{}
+	}
+
+	index.prepareTerms()
+
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+
+	var lastTerm string = prefixTerms[len(prefixTerms)-1]
+
+	//find the documents with a term that starts with the last word
+	var candidates map[string]bool = map[string]bool{}
+	var start int = sort.SearchStrings(index.sortedTerms, lastTerm)
+
+	for i := start; i < len(index.sortedTerms) && strings.HasPrefix(index.sortedTerms[i], lastTerm); i++ {
+		for id, fields := range index.postings[index.sortedTerms[i]] {
+			if fields[field] > 0 {
+				candidates[id] = true
+			}
+		}
+	}
+
+	var suggestions []SearchSuggestion = []SearchSuggestion{}
+
+	for id := range candidates {
+		var text string = index.documents[id].Fields[field]
+
+		//the complete words of the prefix must be in the field as well
+		if hasTerms(Tokenize(text), prefixTerms[:len(prefixTerms)-1]) {
+			suggestions = append(suggestions, SearchSuggestion{ID: id, Text: text})
+		}
+	}
+
+	//the shortest completions are returned first
+	sort.Slice(suggestions, func(i, j int) bool {
+		if len(suggestions[i].Text) != len(suggestions[j].Text) {
+			return len(suggestions[i].Text) < len(suggestions[j].Text)
+		}
+
+		return suggestions[i].Text < suggestions[j].Text
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions
+}
+
+//prepareTerms sorts the terms of the index if they are changed
+func (index *SearchIndex) prepareTerms() {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if index.sorted {
+		return
+	}
+
+	index.sortedTerms = make([]string, 0, len(index.postings))
+	for term := range index.postings {
+		index.sortedTerms = append(index.sortedTerms, term)
+	}
+
+	sort.Strings(index.sortedTerms)
+	index.sorted = true
+}
+
+//matchTerm returns the indexed terms that match the query term with their weight
+func (index *SearchIndex) matchTerm(queryTerm string) map[string]float64 {
+	var matches map[string]float64 = map[string]float64{}
+	var maxEdits int = getMaxEdits(queryTerm)
+
+	for _, term := range index.sortedTerms {
+		switch {
+		case term == queryTerm:
+			matches[term] = exactMatchWeight
+		case len([]rune(queryTerm)) >= 2 && strings.HasPrefix(term, queryTerm):
+			matches[term] = prefixMatchWeight
+		case maxEdits > 0:
+			if distance := levenshtein(queryTerm, term, maxEdits); distance <= maxEdits {
+				matches[term] = fuzzyMatchWeight / float64(distance)
+			}
+		}
+	}
+
+	return matches
+}
+
+//getFieldWeight returns the weight of the field
+func (index *SearchIndex) getFieldWeight(field string) float64 {
+	if weight, ok := index.fieldWeights[field]; ok {
+		return weight
+	}
+
+	return 1
+}
+
+//Tokenize returns the lowercase words of the text
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+//Highlight wraps the words of the text that are in the terms with the <mark> tag
+//the text is HTML escaped, only the <mark> tags are returned as markup
+//false is returned if no word is highlighted
+func Highlight(text string, terms map[string]bool) (string, bool) {
+	var builder strings.Builder
+	var highlighted bool
+	var runes []rune = []rune(text)
+
+	for i := 0; i < len(runes); {
+		//copy the characters between the words
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			builder.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+
+		//find the end of the word
+		var end int = i
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+			end++
+		}
+
+		var word string = html.EscapeString(string(runes[i:end]))
+		if terms[strings.ToLower(word)] {
+			builder.WriteString("<mark>" + word + "</mark>")
+			highlighted = true
+		} else {
+			builder.WriteString(word)
+		}
+
+		i = end
+	}
+
+	return builder.String(), highlighted
+}
+
+//getMaxEdits returns the number of typos allowed for the term
+func getMaxEdits(term string) int {
+	switch length := len([]rune(term)); {
+	case length <= 3:
+		return 0
+	case length <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+//hasTerms returns true if every term is in the words
+func hasTerms(words []string, terms []string) bool {
+	for _, term := range terms {
+		var found bool
+		for _, word := range words {
+			if word == term {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+//levenshtein returns the edit distance between the words
+//the calculation stops when the distance is greater than the maximum
+func levenshtein(a string, b string, max int) int {
+	var first, second []rune = []rune(a), []rune(b)
+
+	//the distance is at least the difference of the lengths
+	if int(math.Abs(float64(len(first)-len(second)))) > max {
+		return max + 1
+	}
+
+	var previous []int = make([]int, len(second)+1)
+	var current []int = make([]int, len(second)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(first); i++ {
+		current[0] = i
+		var rowMinimum int = current[0]
+
+		for j := 1; j <= len(second); j++ {
+			var cost int = 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMinimum = min(rowMinimum, current[j])
+		}
+
+		//stop if every distance of the row is greater than the maximum
+		if rowMinimum > max {
+			return max + 1
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(second)]
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"image/png"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+//the parameters of the TOTP codes, these are the defaults of the authenticator apps
+const (
+	totpPeriod = 30
+	totpDigits = 6
+	totpSkew   = 1
+)
+
+//totpEncoding is the base32 encoding of the TOTP secret
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+//GenerateTOTPSecret returns a new random TOTP secret in the base32 format
+func GenerateTOTPSecret() (string, error) {
+	var secret []byte = make([]byte, 20)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+//GetTOTPURI returns the otpauth URI that is added into the authenticator apps
+func GetTOTPURI(issuer string, accountName string, secret string) string {
+	var query url.Values = url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	var label string = url.PathEscape(issuer + ":" + accountName)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+//GenerateTOTPCode returns the TOTP code for the given time step
+func GenerateTOTPCode(secret string, step int64) (string, error) {
+	//decode the secret
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	//calculate the HMAC of the time step
+	var message []byte = make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	var sum []byte = mac.Sum(nil)
+
+	//truncate the HMAC into the code
+	var offset byte = sum[len(sum)-1] & 0x0f
+	var value uint32 = binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+//ValidateTOTPCode returns the time step of the code if the code is valid
+//the codes of the previous and the next time step are accepted as well
+func ValidateTOTPCode(secret string, code string, now time.Time) (int64, bool) {
+	var currentStep int64 = now.Unix() / totpPeriod
+
+	for step := currentStep - totpSkew; step <= currentStep+totpSkew; step++ {
+		expected, err := GenerateTOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+//GenerateQRCodePNG returns the QR code of the content in the PNG format
+func GenerateQRCodePNG(content string, size int) ([]byte, error) {
+	//encode the content into a QR code
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+
+	//scale the QR code into the requested size
+	code, err = barcode.Scale(code, size, size)
+	if err != nil {
+		return nil, err
+	}
+
+	//encode the QR code into the PNG format
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, code); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}